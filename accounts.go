@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/templates"
+	"github.com/onflow/flow-go/fvm/systemcontracts"
+	"github.com/onflow/flowkit/v2"
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/transactions"
+	jsFlow "github.com/onflowser/flow-cli-wasm/js"
+	"github.com/onflowser/flow-cli-wasm/keys"
+)
+
+type AccountKeyRequest struct {
+	// Hex encoded public key, can be omitted if the private key is provided.
+	PublicKey string `json:"publicKey"`
+	// Hex encoded private key, only required for saving the account to flow.json.
+	PrivateKey string `json:"privateKey"`
+	// Defaults to ECDSA_P256.
+	SigAlgo string `json:"sigAlgo"`
+	// Defaults to SHA3_256.
+	HashAlgo string `json:"hashAlgo"`
+	// Defaults to full weight (1000).
+	Weight int `json:"weight"`
+}
+
+type CreateAccountRequest struct {
+	Keys []AccountKeyRequest `json:"keys"`
+	// Contract source code, deployed to the new account in the given order,
+	// so contracts can import the ones before them. Requires the private key of the first key.
+	Contracts []string `json:"contracts"`
+	// Amount of FLOW (e.g. "10.0") transferred from the service account to the new account.
+	Funding string `json:"funding"`
+	// If set, the account is saved to flow.json with this name, which requires the private key of the first key.
+	Name string `json:"name"`
+}
+
+// createAccount resolves with the address of the new account.
+// The service account (as defined in flow.json) pays for the account creation and funding.
+func (w *FlowWasm) createAccount(this js.Value, args []js.Value) any {
+	rawRequest := args[0].String()
+
+	executor := func() (js.Value, error) {
+		var request CreateAccountRequest
+		err := json.Unmarshal([]byte(rawRequest), &request)
+		if err != nil {
+			return js.Null(), fmt.Errorf("invalid request: %w", err)
+		}
+
+		address, err := w.newAccount(context.Background(), request)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return js.ValueOf(address.String()), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+func (w *FlowWasm) newAccount(ctx context.Context, request CreateAccountRequest) (*sdk.Address, error) {
+	if len(request.Keys) == 0 {
+		return nil, fmt.Errorf("at least one key is required")
+	}
+
+	if request.Keys[0].PrivateKey == "" {
+		if request.Name != "" {
+			return nil, fmt.Errorf("private key of the first key is required to save the account")
+		}
+		if len(request.Contracts) > 0 {
+			return nil, fmt.Errorf("private key of the first key is required to deploy contracts")
+		}
+	}
+	if request.Name != "" {
+		if _, err := w.state.Accounts().ByName(request.Name); err == nil {
+			return nil, fmt.Errorf("account %s already exists", request.Name)
+		}
+	}
+
+	var firstPrivateKey crypto.PrivateKey
+	keys := make([]accounts.PublicKey, 0, len(request.Keys))
+	for i, keyRequest := range request.Keys {
+		key, privateKey, err := parseAccountKey(keyRequest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key at index %d: %w", i, err)
+		}
+		if i == 0 {
+			firstPrivateKey = privateKey
+		}
+		keys = append(keys, accounts.PublicKey{
+			Public:   key.PublicKey,
+			Weight:   key.Weight,
+			SigAlgo:  key.SigAlgo,
+			HashAlgo: key.HashAlgo,
+		})
+	}
+
+	serviceAccount, err := w.state.EmulatorServiceAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	flowAccount, _, err := w.kit.CreateAccount(ctx, serviceAccount, keys)
+	if err != nil {
+		return nil, err
+	}
+	address := flowAccount.Address
+
+	if request.Funding != "" {
+		err = w.fundAccount(ctx, *serviceAccount, address, request.Funding)
+		if err != nil {
+			return nil, fmt.Errorf("account %s was created, but funding failed: %w", address, err)
+		}
+	}
+
+	if len(request.Contracts) > 0 {
+		err = w.deployToNewAccount(ctx, address, firstPrivateKey, keys[0].HashAlgo, request.Contracts)
+		if err != nil {
+			return nil, fmt.Errorf("account %s was created, but %w", address, err)
+		}
+	}
+
+	if request.Name != "" {
+		err = w.saveAccountKey(request.Name, address, firstPrivateKey, keys[0].HashAlgo)
+		if err != nil {
+			return nil, fmt.Errorf("account %s was created, but couldn't be saved: %w", address, err)
+		}
+	}
+
+	return &address, nil
+}
+
+// deployToNewAccount deploys contracts in the given order, signed with the first key of the new account,
+// since only the account itself can authorize adding contracts to it.
+func (w *FlowWasm) deployToNewAccount(
+	ctx context.Context,
+	address sdk.Address,
+	privateKey crypto.PrivateKey,
+	hashAlgo crypto.HashAlgorithm,
+	contracts []string,
+) error {
+	account := &accounts.Account{
+		Address: address,
+		Key:     accounts.NewHexKeyFromPrivateKey(0, hashAlgo, privateKey),
+	}
+
+	for i, source := range contracts {
+		_, _, err := w.kit.AddContract(
+			ctx,
+			account,
+			flowkit.Script{Code: []byte(source)},
+			flowkit.UpdateExistingContract(false),
+		)
+		if err != nil {
+			return fmt.Errorf("contract at index %d couldn't be deployed: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// addKey resolves with the index of the added key.
+// The first argument is the name of the account in flow.json, which signs the transaction.
+func (w *FlowWasm) addKey(this js.Value, args []js.Value) any {
+	accountName := args[0].String()
+	rawKey := args[1].String()
+
+	executor := func() (js.Value, error) {
+		var keyRequest AccountKeyRequest
+		err := json.Unmarshal([]byte(rawKey), &keyRequest)
+		if err != nil {
+			return js.Null(), fmt.Errorf("invalid request: %w", err)
+		}
+
+		account, err := w.state.Accounts().ByName(accountName)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		key, _, err := parseAccountKey(keyRequest)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		tx, err := templates.AddAccountKey(account.Address, key)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		ctx := context.Background()
+		_, err = w.sendTemplateTransaction(ctx, *account, tx)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		// Keys can only be appended, so the new key is the last one.
+		onChainAccount, err := w.gateway.GetAccount(ctx, account.Address)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return js.ValueOf(len(onChainAccount.Keys) - 1), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// revokeKey revokes the key with the given index.
+// The first argument is the name of the account in flow.json, which signs the transaction.
+func (w *FlowWasm) revokeKey(this js.Value, args []js.Value) any {
+	accountName := args[0].String()
+	keyIndex := args[1].Int()
+
+	executor := func() (js.Value, error) {
+		account, err := w.state.Accounts().ByName(accountName)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		if keyIndex == account.Key.Index() {
+			return js.Null(), fmt.Errorf("key %d is used by flow.json to sign transactions for account %s", keyIndex, accountName)
+		}
+
+		tx := templates.RemoveAccountKey(account.Address, keyIndex)
+		_, err = w.sendTemplateTransaction(context.Background(), *account, tx)
+
+		return js.Null(), err
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// listAccounts resolves with all accounts that exist on the emulator,
+// named after the matching flow.json accounts.
+func (w *FlowWasm) listAccounts(this js.Value, args []js.Value) any {
+	executor := func() (js.Value, error) {
+		ctx := context.Background()
+		values := make([]any, 0)
+
+		// Account addresses are generated sequentially, starting at index 1 (service account).
+		for index := uint(1); ; index++ {
+			flowAccount, err := w.gateway.GetAccountByIndex(index)
+			if err != nil {
+				break
+			}
+
+			account, err := w.gateway.GetAccount(ctx, sdk.Address(flowAccount.Address))
+			if err != nil {
+				return js.Null(), err
+			}
+
+			values = append(values, w.serializeAccountSummary(account))
+		}
+
+		return js.ValueOf(values), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+func (w *FlowWasm) serializeAccountSummary(account *sdk.Account) map[string]any {
+	var name any
+	if stateAccount, err := w.state.Accounts().ByAddress(account.Address); err == nil {
+		name = stateAccount.Name
+	}
+
+	contracts := make([]any, 0, len(account.Contracts))
+	for contractName := range account.Contracts {
+		contracts = append(contracts, contractName)
+	}
+
+	keys := make([]any, 0, len(account.Keys))
+	for _, key := range account.Keys {
+		keys = append(keys, map[string]any{
+			"index":     key.Index,
+			"publicKey": strings.TrimPrefix(key.PublicKey.String(), "0x"),
+			"sigAlgo":   key.SigAlgo.String(),
+			"hashAlgo":  key.HashAlgo.String(),
+			"weight":    key.Weight,
+			"revoked":   key.Revoked,
+		})
+	}
+
+	return map[string]any{
+		"address":   account.Address.String(),
+		"name":      name,
+		"balance":   cadence.UFix64(account.Balance).String(),
+		"contracts": contracts,
+		"keys":      keys,
+	}
+}
+
+// parseAccountKey returns a nil private key if only the public key was provided.
+func parseAccountKey(request AccountKeyRequest) (*sdk.AccountKey, crypto.PrivateKey, error) {
+	sigAlgo, hashAlgo, err := keys.ParseAlgorithms(request.SigAlgo, request.HashAlgo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	weight := request.Weight
+	if weight == 0 {
+		weight = sdk.AccountKeyWeightThreshold
+	}
+
+	var privateKey crypto.PrivateKey
+	var publicKey crypto.PublicKey
+	switch {
+	case request.PrivateKey != "":
+		privateKey, err = crypto.DecodePrivateKeyHex(sigAlgo, strings.TrimPrefix(request.PrivateKey, "0x"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		publicKey = privateKey.PublicKey()
+	case request.PublicKey != "":
+		publicKey, err = crypto.DecodePublicKeyHex(sigAlgo, strings.TrimPrefix(request.PublicKey, "0x"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid public key: %w", err)
+		}
+	default:
+		return nil, nil, errors.New("public or private key is required")
+	}
+
+	key := &sdk.AccountKey{
+		PublicKey: publicKey,
+		SigAlgo:   sigAlgo,
+		HashAlgo:  hashAlgo,
+		Weight:    weight,
+	}
+
+	return key, privateKey, key.Validate()
+}
+
+// sendTemplateTransaction signs the transaction built from SDK templates with the given account,
+// which is used as the proposer, payer and the only authorizer.
+func (w *FlowWasm) sendTemplateTransaction(
+	ctx context.Context,
+	signer accounts.Account,
+	tx *sdk.Transaction,
+) (*sdk.TransactionResult, error) {
+	args := make([]cadence.Value, 0, len(tx.Arguments))
+	for _, arg := range tx.Arguments {
+		value, err := jsoncdc.Decode(nil, arg)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	_, result, err := w.kit.SendTransaction(
+		ctx,
+		transactions.SingleAccountRole(signer),
+		flowkit.Script{Code: tx.Script, Args: args},
+		sdk.DefaultTransactionGasLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return result, nil
+}
+
+// language=Cadence
+const transferFlowTransaction = `
+import FungibleToken from 0x%s
+import FlowToken from 0x%s
+
+transaction(amount: UFix64, to: Address) {
+    let sentVault: @{FungibleToken.Vault}
+
+    prepare(signer: auth(BorrowValue) &Account) {
+        let vault = signer.storage.borrow<auth(FungibleToken.Withdraw) &FlowToken.Vault>(from: /storage/flowTokenVault)
+            ?? panic("Could not borrow reference to the signer's vault")
+        self.sentVault <- vault.withdraw(amount: amount)
+    }
+
+    execute {
+        let receiver = getAccount(to).capabilities.borrow<&{FungibleToken.Receiver}>(/public/flowTokenReceiver)
+            ?? panic("Could not borrow receiver reference to the recipient's vault")
+        receiver.deposit(from: <-self.sentVault)
+    }
+}
+`
+
+func (w *FlowWasm) fundAccount(ctx context.Context, signer accounts.Account, address sdk.Address, amount string) error {
+	value, err := cadence.NewUFix64(amount)
+	if err != nil {
+		return fmt.Errorf("invalid amount: %w", err)
+	}
+
+	chainID := w.gateway.Blockchain().GetChain().ChainID()
+	contracts := systemcontracts.SystemContractsForChain(chainID)
+	script := fmt.Sprintf(
+		transferFlowTransaction,
+		contracts.FungibleToken.Address.Hex(),
+		contracts.FlowToken.Address.Hex(),
+	)
+
+	tx := sdk.NewTransaction().
+		SetScript([]byte(script)).
+		AddRawArgument(jsoncdc.MustEncode(value)).
+		AddRawArgument(jsoncdc.MustEncode(cadence.NewAddress(address))).
+		AddAuthorizer(signer.Address)
+
+	_, err = w.sendTemplateTransaction(ctx, signer, tx)
+	return err
+}
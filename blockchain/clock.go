@@ -0,0 +1,107 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// clock follows the system time shifted by an offset, which allows moving block timestamps forward.
+type clock struct {
+	mu     sync.Mutex
+	offset time.Duration
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return time.Now().Add(c.offset)
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.offset += d
+}
+
+// set shifts the clock so that Now returns the given time.
+func (c *clock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.offset = time.Until(t)
+}
+
+// advanceTo shifts the clock so that Now returns at least the given time.
+func (c *clock) advanceTo(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d := time.Until(t); d > c.offset {
+		c.offset = d
+	}
+}
+
+// SetNextBlockTimestamp sets the timestamp of the pending block.
+// The following blocks continue from that timestamp.
+// Transactions that were already executed in the pending block still observe the previous timestamp.
+func (g *Gateway) SetNextBlockTimestamp(timestamp time.Time) error {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	latestBlock, err := g.blockchain.GetLatestBlock()
+	if err != nil {
+		return err
+	}
+
+	if timestamp.Before(latestBlock.Header.Timestamp) {
+		return fmt.Errorf(
+			"timestamp %s is before the latest block timestamp %s",
+			timestamp.UTC().Format(time.RFC3339Nano),
+			latestBlock.Header.Timestamp.UTC().Format(time.RFC3339Nano),
+		)
+	}
+
+	g.clock.set(timestamp)
+	g.blockchain.SetClock(g.clock)
+
+	return nil
+}
+
+// AdvanceTime moves timestamps of the pending block and all the following blocks forward by the given duration.
+func (g *Gateway) AdvanceTime(d time.Duration) error {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	if d < 0 {
+		return fmt.Errorf("duration must not be negative")
+	}
+
+	g.clock.advance(d)
+	g.blockchain.SetClock(g.clock)
+
+	return nil
+}
+
+// MineBlocks commits the given number of blocks and returns the last one.
+// Pending transactions (if any) are included in the first block, the rest are empty.
+func (g *Gateway) MineBlocks(count uint) (*flowgo.Block, error) {
+	if count == 0 {
+		return nil, fmt.Errorf("block count must be positive")
+	}
+
+	var block *flowgo.Block
+	for i := uint(0); i < count; i++ {
+		var err error
+		block, err = g.CommitBlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return block, nil
+}
@@ -0,0 +1,477 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/emulator"
+	sdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flowkit/v2/gateway"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/status"
+)
+
+// Gateway implements flowkit gateway.Gateway interface on top of an in-process emulator blockchain.
+// It mirrors gateway.EmulatorGateway from flowkit, but also exposes the underlying blockchain,
+// which is needed for features that aren't part of the access API (e.g. block height manipulation).
+type Gateway struct {
+	blockchain *emulator.Blockchain
+	adapter    *adapters.SDKAdapter
+	logger     *zerolog.Logger
+	mining     mining
+	clock      *clock
+	// Held exclusively while the blockchain state is replaced, see ReplaceState.
+	state sync.RWMutex
+	// Listeners of committed blocks, see SubscribeBlocks.
+	subscriptions subscriptions
+}
+
+var _ gateway.Gateway = &Gateway{}
+
+// unwrapStatusError only unwraps gRPC status errors, unlike gateway.UnwrapStatusError from flowkit,
+// so that typed errors (e.g. Cadence errors of failed scripts) can still be inspected by callers.
+func unwrapStatusError(err error) error {
+	if s, ok := status.FromError(err); ok {
+		return errors.New(s.Message())
+	}
+	return err
+}
+
+func NewGateway(blockchain *emulator.Blockchain, logger *zerolog.Logger) *Gateway {
+	blockchain.EnableAutoMine()
+
+	g := &Gateway{
+		blockchain: blockchain,
+		adapter:    adapters.NewSDKAdapter(logger, blockchain),
+		logger:     logger,
+		mining: mining{
+			mode: MiningModeAuto,
+		},
+		clock: &clock{},
+	}
+
+	// The restored state may contain blocks from the future (see AdvanceTime),
+	// so make sure new blocks don't end up with earlier timestamps.
+	latestBlock, err := blockchain.GetLatestBlock()
+	if err == nil {
+		g.clock.advanceTo(latestBlock.Header.Timestamp)
+	}
+	blockchain.SetClock(g.clock)
+
+	g.watchBlocks()
+
+	return g
+}
+
+func (g *Gateway) Blockchain() *emulator.Blockchain {
+	return g.blockchain
+}
+
+func (g *Gateway) Logger() *zerolog.Logger {
+	return g.logger
+}
+
+// ReplaceState replaces the storage of the blockchain (e.g. with an imported archive) and reloads the blockchain.
+// Other gateway calls wait until the blockchain is reloaded, so they never observe a partially replaced state.
+func (g *Gateway) ReplaceState(replace func() error) error {
+	g.state.Lock()
+	defer g.state.Unlock()
+
+	err := replace()
+	if err != nil {
+		return err
+	}
+
+	return g.blockchain.ReloadBlockchain()
+}
+
+// ReadState reads the storage of the blockchain (e.g. to export an archive),
+// so that it isn't replaced by ReplaceState or a snapshot while it's being read.
+func (g *Gateway) ReadState(read func() error) error {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	return read()
+}
+
+// CreateSnapshot captures the current state, which can be restored with LoadSnapshot.
+func (g *Gateway) CreateSnapshot(name string) error {
+	g.state.Lock()
+	defer g.state.Unlock()
+
+	// The emulator reloads the blockchain after creating a snapshot.
+	return g.blockchain.CreateSnapshot(name)
+}
+
+// LoadSnapshot restores the state captured with CreateSnapshot.
+// Transactions of the pending block are discarded.
+func (g *Gateway) LoadSnapshot(name string) error {
+	g.state.Lock()
+	defer g.state.Unlock()
+
+	return g.blockchain.LoadSnapshot(name)
+}
+
+// Snapshots returns names of the snapshots created with CreateSnapshot.
+func (g *Gateway) Snapshots() ([]string, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	return g.blockchain.Snapshots()
+}
+
+// DeleteSnapshot runs the given function that deletes a snapshot from the storage,
+// since the emulator doesn't support deleting snapshots.
+func (g *Gateway) DeleteSnapshot(deleteSnapshot func() error) error {
+	g.state.Lock()
+	defer g.state.Unlock()
+
+	return deleteSnapshot()
+}
+
+// AdvanceToBlockHeight commits empty blocks until the latest block is at the given height.
+func (g *Gateway) AdvanceToBlockHeight(height uint64) error {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	latestBlock, err := g.blockchain.GetLatestBlock()
+	if err != nil {
+		return err
+	}
+
+	for current := latestBlock.Header.Height; current < height; current++ {
+		_, err := g.blockchain.CommitBlock()
+		if err != nil {
+			return fmt.Errorf("failed to commit block at height %d: %w", current+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (g *Gateway) GetAccount(ctx context.Context, address sdk.Address) (*sdk.Account, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	account, err := g.adapter.GetAccount(ctx, address)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return account, nil
+}
+
+func (g *Gateway) SendSignedTransaction(ctx context.Context, tx *sdk.Transaction) (*sdk.Transaction, error) {
+	// Not deferred, since the mining lock must not be acquired while holding the state lock.
+	g.state.RLock()
+	err := g.adapter.SendTransaction(ctx, *tx)
+	g.state.RUnlock()
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	g.trackPendingTransaction(tx.ID())
+	return tx, nil
+}
+
+// GetTransactionResult waits until the transaction is sealed if waitSeal is true,
+// which only happens once a block is committed when auto mining is disabled (see SetMiningMode).
+func (g *Gateway) GetTransactionResult(ctx context.Context, ID sdk.Identifier, waitSeal bool) (*sdk.TransactionResult, error) {
+	if waitSeal {
+		return g.waitTransactionSealed(ctx, ID)
+	}
+
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	result, err := g.adapter.GetTransactionResult(ctx, ID)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return result, nil
+}
+
+func (g *Gateway) waitTransactionSealed(ctx context.Context, ID sdk.Identifier) (*sdk.TransactionResult, error) {
+	// Buffered, so that the listener never blocks the notifier.
+	sealed := make(chan *sdk.TransactionResult, 1)
+
+	unsubscribe, err := g.SubscribeTransactionStatus(ctx, ID, func(result *sdk.TransactionResult) {
+		// Unknown transactions will never be sealed.
+		if result.Status == sdk.TransactionStatusSealed || result.Status == sdk.TransactionStatusUnknown {
+			select {
+			case sealed <- result:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer unsubscribe()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-sealed:
+		return result, nil
+	}
+}
+
+// ComputationUsed returns zero unless computation reporting is enabled (see emulator.WithComputationReporting).
+// It's also zero for transactions that were executed before the emulator was (re)started,
+// since the computation report is kept in memory.
+func (g *Gateway) ComputationUsed(ID sdk.Identifier) uint64 {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	return g.blockchain.ComputationReport().Transactions[ID.String()].ComputationUsed
+}
+
+// GetAccountByIndex isn't part of the flowkit gateway.Gateway interface.
+// Account addresses are generated sequentially, starting at index 1 (service account).
+func (g *Gateway) GetAccountByIndex(index uint) (*flowgo.Account, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	return g.blockchain.GetAccountByIndex(index)
+}
+
+func (g *Gateway) GetTransaction(ctx context.Context, ID sdk.Identifier) (*sdk.Transaction, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	tx, err := g.adapter.GetTransaction(ctx, ID)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return tx, nil
+}
+
+func (g *Gateway) GetTransactionResultsByBlockID(ctx context.Context, ID sdk.Identifier) ([]*sdk.TransactionResult, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	results, err := g.adapter.GetTransactionResultsByBlockID(ctx, ID)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return results, nil
+}
+
+func (g *Gateway) GetTransactionsByBlockID(ctx context.Context, ID sdk.Identifier) ([]*sdk.Transaction, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	txs, err := g.adapter.GetTransactionsByBlockID(ctx, ID)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return txs, nil
+}
+
+func (g *Gateway) GetLatestBlock(ctx context.Context) (*sdk.Block, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	block, _, err := g.adapter.GetLatestBlock(ctx, true)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return block, nil
+}
+
+func (g *Gateway) GetBlockByID(ctx context.Context, ID sdk.Identifier) (*sdk.Block, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	block, _, err := g.adapter.GetBlockByID(ctx, ID)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return block, nil
+}
+
+// GetBlockHeaderByID returns the full block header, which includes fields that SDK blocks don't have (e.g. signatures).
+func (g *Gateway) GetBlockHeaderByID(ID sdk.Identifier) (*flowgo.Header, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	block, err := g.blockchain.GetBlockByID(flowgo.Identifier(ID))
+	if err != nil {
+		return nil, err
+	}
+	return block.Header, nil
+}
+
+func (g *Gateway) GetBlockByHeight(ctx context.Context, height uint64) (*sdk.Block, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	block, _, err := g.adapter.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return block, nil
+}
+
+func (g *Gateway) GetCollection(ctx context.Context, ID sdk.Identifier) (*sdk.Collection, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	collection, err := g.adapter.GetCollectionByID(ctx, ID)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return collection, nil
+}
+
+func (g *Gateway) GetEvents(
+	ctx context.Context,
+	eventType string,
+	startHeight uint64,
+	endHeight uint64,
+) ([]sdk.BlockEvents, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	blockEvents, err := g.adapter.GetEventsForHeightRange(ctx, eventType, startHeight, endHeight)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+
+	events := make([]sdk.BlockEvents, 0, len(blockEvents))
+	for _, value := range blockEvents {
+		events = append(events, *value)
+	}
+
+	return events, nil
+}
+
+// GetEventsForBlockIDs isn't part of the flowkit gateway.Gateway interface, which only supports height ranges.
+func (g *Gateway) GetEventsForBlockIDs(
+	ctx context.Context,
+	eventType string,
+	blockIDs []sdk.Identifier,
+) ([]sdk.BlockEvents, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	blockEvents, err := g.adapter.GetEventsForBlockIDs(ctx, eventType, blockIDs)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+
+	events := make([]sdk.BlockEvents, 0, len(blockEvents))
+	for _, value := range blockEvents {
+		events = append(events, *value)
+	}
+
+	return events, nil
+}
+
+func (g *Gateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	args, err := encodeArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := g.adapter.ExecuteScriptAtLatestBlock(ctx, script, args)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+
+	return jsoncdc.Decode(nil, result)
+}
+
+func (g *Gateway) ExecuteScriptAtHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	args, err := encodeArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := g.adapter.ExecuteScriptAtBlockHeight(ctx, height, script, args)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+
+	return jsoncdc.Decode(nil, result)
+}
+
+func (g *Gateway) ExecuteScriptAtID(ctx context.Context, script []byte, arguments []cadence.Value, ID sdk.Identifier) (cadence.Value, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	args, err := encodeArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := g.adapter.ExecuteScriptAtBlockID(ctx, ID, script, args)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+
+	return jsoncdc.Decode(nil, result)
+}
+
+func encodeArguments(values []cadence.Value) ([][]byte, error) {
+	args := make([][]byte, 0, len(values))
+	for _, value := range values {
+		arg, err := jsoncdc.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument: %w", err)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func (g *Gateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	snapshot, err := g.adapter.GetLatestProtocolStateSnapshot(ctx)
+	if err != nil {
+		return nil, unwrapStatusError(err)
+	}
+	return snapshot, nil
+}
+
+func (g *Gateway) Ping() error {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	err := g.adapter.Ping(context.Background())
+	if err != nil {
+		return unwrapStatusError(err)
+	}
+	return nil
+}
+
+// WaitServer returns immediately, since the blockchain runs in the same process.
+func (g *Gateway) WaitServer(ctx context.Context) error {
+	return nil
+}
+
+// SecureConnection is always false, since the blockchain runs in the same process.
+func (g *Gateway) SecureConnection() bool {
+	return false
+}
+
+func (g *Gateway) CoverageReport() *runtime.CoverageReport {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	return g.blockchain.CoverageReport()
+}
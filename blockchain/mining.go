@@ -0,0 +1,147 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+type MiningMode string
+
+const (
+	// MiningModeAuto commits a block for every transaction.
+	MiningModeAuto MiningMode = "auto"
+	// MiningModeManual only commits blocks with CommitBlock.
+	MiningModeManual MiningMode = "manual"
+	// MiningModeInterval commits a block (including empty ones) at a fixed interval.
+	MiningModeInterval MiningMode = "interval"
+)
+
+type mining struct {
+	mu   sync.Mutex
+	mode MiningMode
+	// Closed to stop the interval mining loop.
+	stop chan struct{}
+	// Transactions that were sent while auto mining was disabled.
+	// Some of these may have been committed already, see PendingTransactions.
+	sentTransactionIDs []sdk.Identifier
+}
+
+func (g *Gateway) MiningMode() MiningMode {
+	g.mining.mu.Lock()
+	defer g.mining.mu.Unlock()
+
+	return g.mining.mode
+}
+
+// SetMiningMode switches the way blocks are produced.
+// Interval is only used with MiningModeInterval.
+func (g *Gateway) SetMiningMode(mode MiningMode, interval time.Duration) error {
+	g.mining.mu.Lock()
+	defer g.mining.mu.Unlock()
+
+	switch mode {
+	case MiningModeAuto, MiningModeManual:
+	case MiningModeInterval:
+		if interval <= 0 {
+			return fmt.Errorf("mining interval must be positive")
+		}
+	default:
+		return fmt.Errorf("unsupported mining mode: %s", mode)
+	}
+
+	if g.mining.stop != nil {
+		close(g.mining.stop)
+		g.mining.stop = nil
+	}
+
+	if mode != MiningModeAuto {
+		g.blockchain.DisableAutoMine()
+	} else {
+		g.blockchain.EnableAutoMine()
+
+		// Transactions sent in manual mode would otherwise wait for the next transaction.
+		if len(g.pendingTransactionIDs()) > 0 {
+			_, err := g.CommitBlock()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if mode == MiningModeInterval {
+		g.mining.stop = make(chan struct{})
+		go g.mineAtInterval(interval, g.mining.stop)
+	}
+
+	g.mining.mode = mode
+
+	return nil
+}
+
+func (g *Gateway) mineAtInterval(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			_, err := g.CommitBlock()
+			if err != nil {
+				g.logger.Error().Err(err).Msg("failed to commit block")
+			}
+		}
+	}
+}
+
+// CommitBlock executes pending transactions (if any) and commits them in a new block.
+func (g *Gateway) CommitBlock() (*flowgo.Block, error) {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	block, _, err := g.blockchain.ExecuteAndCommitBlock()
+	return block, err
+}
+
+// PendingTransactions returns IDs of transactions that were sent, but not yet committed.
+func (g *Gateway) PendingTransactions() []sdk.Identifier {
+	g.mining.mu.Lock()
+	defer g.mining.mu.Unlock()
+
+	g.mining.sentTransactionIDs = g.pendingTransactionIDs()
+
+	return g.mining.sentTransactionIDs
+}
+
+func (g *Gateway) trackPendingTransaction(id sdk.Identifier) {
+	g.mining.mu.Lock()
+	defer g.mining.mu.Unlock()
+
+	if g.mining.mode == MiningModeAuto {
+		return
+	}
+
+	g.mining.sentTransactionIDs = append(g.mining.sentTransactionIDs, id)
+}
+
+// pendingTransactionIDs must be called with the mining lock held.
+func (g *Gateway) pendingTransactionIDs() []sdk.Identifier {
+	g.state.RLock()
+	defer g.state.RUnlock()
+
+	pending := make([]sdk.Identifier, 0)
+
+	for _, id := range g.mining.sentTransactionIDs {
+		result, err := g.blockchain.GetTransactionResult(flowgo.Identifier(id))
+		if err == nil && result.Status == flowgo.TransactionStatusPending {
+			pending = append(pending, id)
+		}
+	}
+
+	return pending
+}
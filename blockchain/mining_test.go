@@ -0,0 +1,288 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-emulator/emulator"
+	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/rs/zerolog"
+)
+
+func newTestGateway(t *testing.T) *Gateway {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	b, err := emulator.New(emulator.WithLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to create emulator: %v", err)
+	}
+
+	g := NewGateway(b, &logger)
+	t.Cleanup(func() {
+		// Stops the interval mining loop.
+		_ = g.SetMiningMode(MiningModeAuto, 0)
+	})
+
+	return g
+}
+
+// sendEmptyTransaction sends a transaction that is proposed, paid for and authorized by the service account.
+func sendEmptyTransaction(t *testing.T, g *Gateway) sdk.Identifier {
+	t.Helper()
+
+	ctx := context.Background()
+	serviceKey := g.Blockchain().ServiceKey()
+
+	latestBlock, err := g.GetLatestBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := g.GetAccount(ctx, serviceKey.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := sdk.NewTransaction().
+		SetScript([]byte("transaction { prepare(signer: &Account) {} }")).
+		SetReferenceBlockID(latestBlock.ID).
+		SetProposalKey(serviceKey.Address, serviceKey.Index, account.Keys[serviceKey.Index].SequenceNumber).
+		SetPayer(serviceKey.Address).
+		AddAuthorizer(serviceKey.Address)
+
+	signer, err := serviceKey.Signer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = tx.SignEnvelope(serviceKey.Address, serviceKey.Index, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = g.SendSignedTransaction(ctx, tx)
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+
+	return tx.ID()
+}
+
+func TestSetMiningMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     MiningMode
+		interval time.Duration
+		wantErr  bool
+	}{
+		{
+			name: "auto",
+			mode: MiningModeAuto,
+		},
+		{
+			name: "manual",
+			mode: MiningModeManual,
+		},
+		{
+			name:     "interval",
+			mode:     MiningModeInterval,
+			interval: time.Hour,
+		},
+		{
+			name:     "interval without duration",
+			mode:     MiningModeInterval,
+			interval: 0,
+			wantErr:  true,
+		},
+		{
+			name:    "unsupported mode",
+			mode:    MiningMode("instant"),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTestGateway(t)
+
+			err := g.SetMiningMode(test.mode, test.interval)
+
+			expectedMode := test.mode
+			if test.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				// Mode doesn't change if it's rejected.
+				expectedMode = MiningModeAuto
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mode := g.MiningMode(); mode != expectedMode {
+				t.Errorf("mining mode is %s, expected %s", mode, expectedMode)
+			}
+		})
+	}
+}
+
+func TestMiningModeTransitions(t *testing.T) {
+	tests := []struct {
+		name string
+		// Mode the transaction is sent in.
+		mode MiningMode
+		// Mode that is set after the transaction was sent.
+		nextMode MiningMode
+		// Whether the transaction is still pending after switching to the next mode.
+		pending bool
+	}{
+		{
+			name:     "auto commits immediately",
+			mode:     MiningModeAuto,
+			nextMode: MiningModeManual,
+			pending:  false,
+		},
+		{
+			name:     "manual keeps the transaction pending",
+			mode:     MiningModeManual,
+			nextMode: MiningModeManual,
+			pending:  true,
+		},
+		{
+			name:     "switching from manual to auto commits pending transactions",
+			mode:     MiningModeManual,
+			nextMode: MiningModeAuto,
+			pending:  false,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTestGateway(t)
+
+			err := g.SetMiningMode(test.mode, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			txID := sendEmptyTransaction(t, g)
+
+			err = g.SetMiningMode(test.nextMode, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pending := slices.Contains(g.PendingTransactions(), txID)
+			if pending != test.pending {
+				t.Errorf("transaction pending is %t, expected %t", pending, test.pending)
+			}
+
+			result, err := g.GetTransactionResult(ctx, txID, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sealed := result.Status == sdk.TransactionStatusSealed
+			if sealed == test.pending {
+				t.Errorf("transaction status is %s", result.Status)
+			}
+		})
+	}
+}
+
+func TestCommitBlockIncludesPendingTransactions(t *testing.T) {
+	g := newTestGateway(t)
+
+	err := g.SetMiningMode(MiningModeManual, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txID := sendEmptyTransaction(t, g)
+
+	block, err := g.CommitBlock()
+	if err != nil {
+		t.Fatalf("failed to commit block: %v", err)
+	}
+
+	if len(g.PendingTransactions()) > 0 {
+		t.Error("expected no pending transactions after the block was committed")
+	}
+
+	result, err := g.GetTransactionResult(context.Background(), txID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BlockHeight != block.Header.Height {
+		t.Errorf("transaction was committed at height %d, expected %d", result.BlockHeight, block.Header.Height)
+	}
+}
+
+func TestWaitSealInManualMode(t *testing.T) {
+	g := newTestGateway(t)
+
+	err := g.SetMiningMode(MiningModeManual, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txID := sendEmptyTransaction(t, g)
+
+	results := make(chan *sdk.TransactionResult, 1)
+	errs := make(chan error, 1)
+	go func() {
+		result, err := g.GetTransactionResult(context.Background(), txID, true)
+		if err != nil {
+			errs <- err
+			return
+		}
+		results <- result
+	}()
+
+	select {
+	case result := <-results:
+		t.Fatalf("transaction result with status %s was returned before the block was committed", result.Status)
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_, err = g.CommitBlock()
+	if err != nil {
+		t.Fatalf("failed to commit block: %v", err)
+	}
+
+	select {
+	case result := <-results:
+		if result.Status != sdk.TransactionStatusSealed {
+			t.Errorf("transaction status is %s, expected sealed", result.Status)
+		}
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("transaction result wasn't returned after the block was committed")
+	}
+}
+
+func TestWaitSealCancelled(t *testing.T) {
+	g := newTestGateway(t)
+
+	err := g.SetMiningMode(MiningModeManual, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txID := sendEmptyTransaction(t, g)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = g.GetTransactionResult(ctx, txID, true)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error is %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
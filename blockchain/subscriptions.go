@@ -0,0 +1,260 @@
+package blockchain
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go/engine"
+)
+
+type BlockListener func(block *sdk.Block)
+
+type TransactionStatusListener func(result *sdk.TransactionResult)
+
+type EventListener func(event sdk.Event, block *sdk.Block)
+
+// EventFilter matches events by type and by the address of the contract that emitted them.
+// Empty lists match all events.
+type EventFilter struct {
+	EventTypes []string
+	Addresses  []sdk.Address
+}
+
+func (f EventFilter) matches(event sdk.Event) bool {
+	if len(f.EventTypes) > 0 && !slices.Contains(f.EventTypes, event.Type) {
+		return false
+	}
+
+	if len(f.Addresses) > 0 {
+		address, ok := eventAddress(event.Type)
+		if !ok || !slices.Contains(f.Addresses, address) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eventAddress parses the contract address from event types in format "A.<address>.<contract>.<event>".
+// Built-in events (e.g. "flow.AccountCreated") don't have an address.
+func eventAddress(eventType string) (sdk.Address, bool) {
+	parts := strings.Split(eventType, ".")
+	if len(parts) != 4 || parts[0] != "A" {
+		return sdk.EmptyAddress, false
+	}
+
+	return sdk.HexToAddress(parts[1]), true
+}
+
+type transactionSubscription struct {
+	id       sdk.Identifier
+	status   sdk.TransactionStatus
+	listener TransactionStatusListener
+}
+
+type eventSubscription struct {
+	filter   EventFilter
+	listener EventListener
+}
+
+type subscriptions struct {
+	mu           sync.Mutex
+	nextID       int
+	blocks       map[int]BlockListener
+	transactions map[int]*transactionSubscription
+	events       map[int]*eventSubscription
+	// Height of the latest block that listeners were notified about.
+	height uint64
+}
+
+// watchBlocks notifies subscribers about each committed block.
+// The emulator broadcaster doesn't support unsubscribing, so a single notifier is shared by all subscriptions.
+func (g *Gateway) watchBlocks() {
+	g.subscriptions.blocks = make(map[int]BlockListener)
+	g.subscriptions.transactions = make(map[int]*transactionSubscription)
+	g.subscriptions.events = make(map[int]*eventSubscription)
+
+	latestBlock, err := g.blockchain.GetLatestBlock()
+	if err == nil {
+		g.subscriptions.height = latestBlock.Header.Height
+	}
+
+	notifier := engine.NewNotifier()
+	g.blockchain.Broadcaster().Subscribe(notifier)
+
+	go func() {
+		for range notifier.Channel() {
+			g.notifySubscribers()
+		}
+	}()
+}
+
+// notifySubscribers processes all blocks committed since the last notification,
+// since notifications are coalesced when multiple blocks are committed at once.
+func (g *Gateway) notifySubscribers() {
+	ctx := context.Background()
+
+	latestBlock, err := g.GetLatestBlock(ctx)
+	if err != nil {
+		g.logger.Error().Err(err).Msg("failed to get latest block")
+		return
+	}
+
+	g.subscriptions.mu.Lock()
+	from := g.subscriptions.height + 1
+	// Blockchain was rolled back or replaced (e.g. snapshot loaded).
+	if latestBlock.Height < g.subscriptions.height {
+		from = latestBlock.Height
+	}
+	g.subscriptions.height = latestBlock.Height
+	g.subscriptions.mu.Unlock()
+
+	for height := from; height <= latestBlock.Height; height++ {
+		block, err := g.GetBlockByHeight(ctx, height)
+		if err != nil {
+			g.logger.Error().Err(err).Uint64("height", height).Msg("failed to get block")
+			return
+		}
+
+		g.notifyBlock(ctx, block)
+	}
+}
+
+func (g *Gateway) notifyBlock(ctx context.Context, block *sdk.Block) {
+	g.subscriptions.mu.Lock()
+	blockListeners := make([]BlockListener, 0, len(g.subscriptions.blocks))
+	for _, listener := range g.subscriptions.blocks {
+		blockListeners = append(blockListeners, listener)
+	}
+	eventSubscriptions := make([]*eventSubscription, 0, len(g.subscriptions.events))
+	for _, subscription := range g.subscriptions.events {
+		eventSubscriptions = append(eventSubscriptions, subscription)
+	}
+	g.subscriptions.mu.Unlock()
+
+	for _, listener := range blockListeners {
+		listener(block)
+	}
+
+	if len(eventSubscriptions) > 0 {
+		results, err := g.GetTransactionResultsByBlockID(ctx, block.ID)
+		if err != nil {
+			g.logger.Error().Err(err).Uint64("height", block.Height).Msg("failed to get transaction results")
+		}
+
+		for _, result := range results {
+			for _, event := range result.Events {
+				for _, subscription := range eventSubscriptions {
+					if subscription.filter.matches(event) {
+						subscription.listener(event, block)
+					}
+				}
+			}
+		}
+	}
+
+	g.notifyTransactionStatus(ctx)
+}
+
+func (g *Gateway) notifyTransactionStatus(ctx context.Context) {
+	type update struct {
+		listener TransactionStatusListener
+		result   *sdk.TransactionResult
+	}
+
+	g.subscriptions.mu.Lock()
+	updates := make([]update, 0)
+	for key, subscription := range g.subscriptions.transactions {
+		result, err := g.GetTransactionResult(ctx, subscription.id, false)
+		if err != nil || result.Status == subscription.status {
+			continue
+		}
+
+		subscription.status = result.Status
+		// Sealed is the final status in the emulator.
+		if result.Status == sdk.TransactionStatusSealed {
+			delete(g.subscriptions.transactions, key)
+		}
+
+		updates = append(updates, update{subscription.listener, result})
+	}
+	g.subscriptions.mu.Unlock()
+
+	for _, u := range updates {
+		u.listener(u.result)
+	}
+}
+
+// SubscribeBlocks calls the listener for every committed block until unsubscribed.
+func (g *Gateway) SubscribeBlocks(listener BlockListener) (unsubscribe func()) {
+	g.subscriptions.mu.Lock()
+	defer g.subscriptions.mu.Unlock()
+
+	key := g.subscriptions.nextID
+	g.subscriptions.nextID++
+	g.subscriptions.blocks[key] = listener
+
+	return func() {
+		g.subscriptions.mu.Lock()
+		defer g.subscriptions.mu.Unlock()
+
+		delete(g.subscriptions.blocks, key)
+	}
+}
+
+// SubscribeEvents calls the listener for every committed event that matches the filter until unsubscribed.
+func (g *Gateway) SubscribeEvents(filter EventFilter, listener EventListener) (unsubscribe func()) {
+	g.subscriptions.mu.Lock()
+	defer g.subscriptions.mu.Unlock()
+
+	key := g.subscriptions.nextID
+	g.subscriptions.nextID++
+	g.subscriptions.events[key] = &eventSubscription{
+		filter:   filter,
+		listener: listener,
+	}
+
+	return func() {
+		g.subscriptions.mu.Lock()
+		defer g.subscriptions.mu.Unlock()
+
+		delete(g.subscriptions.events, key)
+	}
+}
+
+// SubscribeTransactionStatus calls the listener with the current transaction result
+// and then every time the transaction status changes, until the transaction is sealed or unsubscribed.
+func (g *Gateway) SubscribeTransactionStatus(
+	ctx context.Context,
+	id sdk.Identifier,
+	listener TransactionStatusListener,
+) (unsubscribe func(), err error) {
+	result, err := g.GetTransactionResult(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	g.subscriptions.mu.Lock()
+	key := g.subscriptions.nextID
+	g.subscriptions.nextID++
+	if result.Status != sdk.TransactionStatusSealed {
+		g.subscriptions.transactions[key] = &transactionSubscription{
+			id:       id,
+			status:   result.Status,
+			listener: listener,
+		}
+	}
+	g.subscriptions.mu.Unlock()
+
+	listener(result)
+
+	return func() {
+		g.subscriptions.mu.Lock()
+		defer g.subscriptions.mu.Unlock()
+
+		delete(g.subscriptions.transactions, key)
+	}, nil
+}
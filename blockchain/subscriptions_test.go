@@ -0,0 +1,124 @@
+package blockchain
+
+import (
+	"testing"
+
+	sdk "github.com/onflow/flow-go-sdk"
+)
+
+func TestEventAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		address   sdk.Address
+		ok        bool
+	}{
+		{
+			name:      "contract event",
+			eventType: "A.f8d6e0586b0a20c7.Counter.Incremented",
+			address:   sdk.HexToAddress("f8d6e0586b0a20c7"),
+			ok:        true,
+		},
+		{
+			name:      "built-in event",
+			eventType: "flow.AccountCreated",
+			ok:        false,
+		},
+		{
+			name:      "missing event name",
+			eventType: "A.f8d6e0586b0a20c7.Counter",
+			ok:        false,
+		},
+		{
+			name:      "unknown prefix",
+			eventType: "B.f8d6e0586b0a20c7.Counter.Incremented",
+			ok:        false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address, ok := eventAddress(test.eventType)
+			if ok != test.ok {
+				t.Fatalf("ok is %t, expected %t", ok, test.ok)
+			}
+			if address != test.address {
+				t.Errorf("address is %s, expected %s", address, test.address)
+			}
+		})
+	}
+}
+
+func TestEventFilterMatches(t *testing.T) {
+	const counterEvent = "A.f8d6e0586b0a20c7.Counter.Incremented"
+	counterAddress := sdk.HexToAddress("f8d6e0586b0a20c7")
+	otherAddress := sdk.HexToAddress("01cf0e2f2f715450")
+
+	tests := []struct {
+		name      string
+		filter    EventFilter
+		eventType string
+		matches   bool
+	}{
+		{
+			name:      "empty filter matches contract events",
+			filter:    EventFilter{},
+			eventType: counterEvent,
+			matches:   true,
+		},
+		{
+			name:      "empty filter matches built-in events",
+			filter:    EventFilter{},
+			eventType: "flow.AccountCreated",
+			matches:   true,
+		},
+		{
+			name:      "matching event type",
+			filter:    EventFilter{EventTypes: []string{"flow.AccountCreated", counterEvent}},
+			eventType: counterEvent,
+			matches:   true,
+		},
+		{
+			name:      "other event type",
+			filter:    EventFilter{EventTypes: []string{"flow.AccountCreated"}},
+			eventType: counterEvent,
+			matches:   false,
+		},
+		{
+			name:      "matching address",
+			filter:    EventFilter{Addresses: []sdk.Address{otherAddress, counterAddress}},
+			eventType: counterEvent,
+			matches:   true,
+		},
+		{
+			name:      "other address",
+			filter:    EventFilter{Addresses: []sdk.Address{otherAddress}},
+			eventType: counterEvent,
+			matches:   false,
+		},
+		{
+			name:      "built-in events don't match addresses",
+			filter:    EventFilter{Addresses: []sdk.Address{counterAddress}},
+			eventType: "flow.AccountCreated",
+			matches:   false,
+		},
+		{
+			name: "event type and address must both match",
+			filter: EventFilter{
+				EventTypes: []string{counterEvent},
+				Addresses:  []sdk.Address{otherAddress},
+			},
+			eventType: counterEvent,
+			matches:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := test.filter.matches(sdk.Event{Type: test.eventType})
+			if matches != test.matches {
+				t.Errorf("matches is %t, expected %t", matches, test.matches)
+			}
+		})
+	}
+}
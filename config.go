@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-go-sdk/crypto"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flowkit/v2/config"
+)
+
+// EmulatorConfig is provided from JS as JSON encoded "emulatorConfig" global.
+// Zero values fallback to the defaults of the in-browser emulator.
+// See EmulatorConfig in /ts-lib/src/index.ts
+type EmulatorConfig struct {
+	// Verifies transaction signatures and proposal key sequence numbers.
+	TransactionValidation bool `json:"transactionValidation"`
+	StorageLimit          bool `json:"storageLimit"`
+	TransactionFees       bool `json:"transactionFees"`
+	// Hex encoded private key of the service account.
+	// Must match the key of the emulator account in flow.json to deploy contracts.
+	ServicePrivateKey string `json:"servicePrivateKey"`
+	// Algorithms of the service private key (e.g. "ECDSA_P256" and "SHA3_256").
+	ServiceKeySigAlgo  string `json:"serviceKeySigAlgo"`
+	ServiceKeyHashAlgo string `json:"serviceKeyHashAlgo"`
+	// One of "flow-emulator", "flow-testnet", "flow-previewnet" or "flow-mainnet".
+	ChainID                string `json:"chainId"`
+	ScriptGasLimit         uint64 `json:"scriptGasLimit"`
+	TransactionMaxGasLimit uint64 `json:"transactionMaxGasLimit"`
+	// Empty blocks are committed on startup until this height is reached.
+	InitialBlockHeight uint64 `json:"initialBlockHeight"`
+	// Persists the emulator state to the project file system, so that it survives page reloads.
+	PersistState bool `json:"persistState"`
+	// Network (e.g. "mainnet") to fork the state from, see forkNetwork.
+	Fork string `json:"fork"`
+	// Defaults to the latest sealed block of the forked network.
+	ForkHeight uint64 `json:"forkHeight"`
+	// Reports computation used by transactions, which is otherwise always zero.
+	// Disabled by default, since the report is kept in memory and grows with every transaction and script.
+	ComputationReporting bool `json:"computationReporting"`
+	// Network from flow.json that is used for resolving contract aliases and accounts.
+	Network string `json:"network"`
+}
+
+var supportedChainIDs = map[flowgo.ChainID]bool{
+	flowgo.Emulator:   true,
+	flowgo.Testnet:    true,
+	flowgo.Previewnet: true,
+	flowgo.Mainnet:    true,
+}
+
+func parseEmulatorConfig(value js.Value) (EmulatorConfig, error) {
+	var emulatorConfig EmulatorConfig
+
+	if value.IsUndefined() || value.IsNull() {
+		return emulatorConfig, nil
+	}
+
+	err := json.Unmarshal([]byte(value.String()), &emulatorConfig)
+	if err != nil {
+		return emulatorConfig, fmt.Errorf("invalid emulator config: %w", err)
+	}
+
+	return emulatorConfig, nil
+}
+
+func (c EmulatorConfig) networkName() string {
+	if c.Network == "" {
+		return config.EmulatorNetwork.Name
+	}
+	return c.Network
+}
+
+func (c EmulatorConfig) options() ([]emulator.Option, error) {
+	serviceKeyOption, err := c.serviceKeyOption()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []emulator.Option{
+		serviceKeyOption,
+		emulator.WithTransactionValidationEnabled(c.TransactionValidation),
+		emulator.WithStorageLimitEnabled(c.StorageLimit),
+		emulator.WithTransactionFeesEnabled(c.TransactionFees),
+		emulator.WithComputationReporting(c.ComputationReporting),
+	}
+
+	chainID, err := c.chainID()
+	if err != nil {
+		return nil, err
+	}
+	if chainID != "" {
+		options = append(options, emulator.WithChainID(chainID))
+	}
+
+	if c.ScriptGasLimit != 0 {
+		options = append(options, emulator.WithScriptGasLimit(c.ScriptGasLimit))
+	}
+
+	if c.TransactionMaxGasLimit != 0 {
+		options = append(options, emulator.WithTransactionMaxGasLimit(c.TransactionMaxGasLimit))
+	}
+
+	return options, nil
+}
+
+// chainID returns an empty value if the default chain ID should be used.
+func (c EmulatorConfig) chainID() (flowgo.ChainID, error) {
+	chainID := flowgo.ChainID(c.ChainID)
+	if chainID != "" && !supportedChainIDs[chainID] {
+		return "", fmt.Errorf("unsupported chain ID: %s", c.ChainID)
+	}
+
+	if c.Fork == "" {
+		return chainID, nil
+	}
+
+	forkChainID, ok := forkChainIDs[c.Fork]
+	if !ok {
+		return "", fmt.Errorf("unsupported fork network: %s", c.Fork)
+	}
+
+	if chainID != "" && chainID != forkChainID {
+		return "", fmt.Errorf("chain ID %s doesn't match the forked network %s", chainID, c.Fork)
+	}
+
+	return forkChainID, nil
+}
+
+func (c EmulatorConfig) serviceKeyOption() (emulator.Option, error) {
+	if c.ServicePrivateKey == "" {
+		return emulator.WithServicePublicKey(
+			emulator.DefaultServiceKey().AccountKey().PublicKey,
+			emulator.DefaultServiceKeySigAlgo,
+			emulator.DefaultServiceKeyHashAlgo,
+		), nil
+	}
+
+	sigAlgo := emulator.DefaultServiceKeySigAlgo
+	if c.ServiceKeySigAlgo != "" {
+		sigAlgo = crypto.StringToSignatureAlgorithm(c.ServiceKeySigAlgo)
+		if sigAlgo == crypto.UnknownSignatureAlgorithm {
+			return nil, fmt.Errorf("unsupported service key signature algorithm: %s", c.ServiceKeySigAlgo)
+		}
+	}
+
+	hashAlgo := emulator.DefaultServiceKeyHashAlgo
+	if c.ServiceKeyHashAlgo != "" {
+		hashAlgo = crypto.StringToHashAlgorithm(c.ServiceKeyHashAlgo)
+		if hashAlgo == crypto.UnknownHashAlgorithm {
+			return nil, fmt.Errorf("unsupported service key hash algorithm: %s", c.ServiceKeyHashAlgo)
+		}
+	}
+
+	privateKey, err := crypto.DecodePrivateKeyHex(sigAlgo, strings.TrimPrefix(c.ServicePrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid service private key: %w", err)
+	}
+
+	return emulator.WithServicePrivateKey(privateKey, sigAlgo, hashAlgo), nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"syscall/js"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/flowkit/v2"
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/output"
+	"github.com/onflow/flowkit/v2/project"
+	jsFlow "github.com/onflowser/flow-cli-wasm/js"
+)
+
+type DeployRequest struct {
+	// Defaults to "emulator".
+	Network string `json:"network"`
+	// Names of contracts to deploy, all contracts of the network deployment are deployed if empty.
+	Contracts []string `json:"contracts"`
+	// Names of flow.json accounts to deploy contracts to, all accounts are deployed if empty.
+	Accounts []string `json:"accounts"`
+	// Whether existing contracts are updated or the deployment fails, defaults to true.
+	Update *bool `json:"update"`
+	// Resolves with the planned deployment without sending any transactions.
+	DryRun bool `json:"dryRun"`
+}
+
+type deployAction string
+
+const (
+	deployActionCreate = deployAction("create")
+	deployActionUpdate = deployAction("update")
+	// Contract is already deployed with the same code.
+	deployActionUnchanged = deployAction("unchanged")
+	// Contract is already deployed, but updates are disabled.
+	deployActionConflict = deployAction("conflict")
+)
+
+type plannedContract struct {
+	contract *project.Contract
+	// Addresses of imported contracts by contract name.
+	imports       map[string]any
+	action        deployAction
+	transactionID string
+}
+
+// deploy accepts an optional JSON encoded DeployRequest
+// and resolves with the contracts in deployment order.
+func (w *FlowWasm) deploy(this js.Value, args []js.Value) any {
+	rawRequest := "{}"
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		rawRequest = args[0].String()
+	}
+
+	executor := func() (js.Value, error) {
+		var request DeployRequest
+		err := json.Unmarshal([]byte(rawRequest), &request)
+		if err != nil {
+			return js.Null(), fmt.Errorf("invalid request: %w", err)
+		}
+
+		if request.Network == "" {
+			request.Network = config.EmulatorNetwork.Name
+		}
+		update := request.Update == nil || *request.Update
+
+		kit, err := w.kitForNetwork(request.Network)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		ctx := context.Background()
+		plan, err := w.planDeployment(ctx, kit, request, update)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		if !request.DryRun {
+			err = w.executeDeployment(ctx, kit, plan, update)
+			if err != nil {
+				return js.Null(), err
+			}
+		}
+
+		return js.ValueOf(serializeDeploymentPlan(plan)), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// planDeployment sorts contracts of the network deployment by their dependencies
+// and resolves their imports the same way as they are resolved when deploying.
+func (w *FlowWasm) planDeployment(
+	ctx context.Context,
+	kit *flowkit.Flowkit,
+	request DeployRequest,
+	update bool,
+) ([]*plannedContract, error) {
+	network, err := w.state.Networks().ByName(request.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := w.state.DeploymentContractsByNetwork(*network)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := w.state.AliasesForNetwork(*network)
+	deployment, err := project.NewDeployment(contracts, aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted, err := deployment.Sort()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range request.Contracts {
+		if !slices.ContainsFunc(sorted, func(c *project.Contract) bool { return c.Name == name }) {
+			return nil, fmt.Errorf("contract %s is not deployed on network %s", name, network.Name)
+		}
+	}
+	for _, name := range request.Accounts {
+		if !slices.ContainsFunc(sorted, func(c *project.Contract) bool { return c.AccountName == name }) {
+			return nil, fmt.Errorf("account %s has no contracts deployed on network %s", name, network.Name)
+		}
+	}
+
+	importReplacer := project.NewImportReplacer(contracts, aliases)
+
+	plan := make([]*plannedContract, 0, len(sorted))
+	for _, contract := range sorted {
+		if len(request.Contracts) > 0 && !slices.Contains(request.Contracts, contract.Name) {
+			continue
+		}
+		if len(request.Accounts) > 0 && !slices.Contains(request.Accounts, contract.AccountName) {
+			continue
+		}
+
+		program, err := project.NewProgram(contract.Code(), contract.Args, contract.Location())
+		if err != nil {
+			return nil, err
+		}
+
+		if program.HasImports() {
+			program, err = importReplacer.Replace(program)
+			if err != nil {
+				return nil, fmt.Errorf("error resolving imports of contract %s: %w", contract.Name, err)
+			}
+		}
+
+		imports := make(map[string]any)
+		for _, declaration := range program.AddressImportDeclarations() {
+			location, ok := declaration.Location.(common.AddressLocation)
+			if !ok {
+				continue
+			}
+			for _, identifier := range declaration.Identifiers {
+				imports[identifier.Identifier] = location.Address.HexWithPrefix()
+			}
+		}
+
+		account, err := kit.GetAccount(ctx, contract.AccountAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", contract.AccountName, err)
+		}
+
+		existingCode, exists := account.Contracts[contract.Name]
+		action := deployActionCreate
+		switch {
+		case exists && bytes.Equal(existingCode, program.Code()):
+			action = deployActionUnchanged
+		case exists && update:
+			action = deployActionUpdate
+		case exists:
+			action = deployActionConflict
+		}
+
+		plan = append(plan, &plannedContract{
+			contract: contract,
+			imports:  imports,
+			action:   action,
+		})
+	}
+
+	return plan, nil
+}
+
+func (w *FlowWasm) executeDeployment(
+	ctx context.Context,
+	kit *flowkit.Flowkit,
+	plan []*plannedContract,
+	update bool,
+) error {
+	failed := 0
+	for _, planned := range plan {
+		contract := planned.contract
+
+		if planned.action == deployActionUnchanged {
+			w.logger.Info(fmt.Sprintf("skipping %s contract, no changes found", contract.Name))
+			continue
+		}
+
+		account, err := w.state.Accounts().ByName(contract.AccountName)
+		if err != nil {
+			return fmt.Errorf("target account %s for contract %s not found: %w", contract.AccountName, contract.Name, err)
+		}
+
+		txID, _, err := kit.AddContract(
+			ctx,
+			account,
+			flowkit.Script{Code: contract.Code(), Args: contract.Args, Location: contract.Location()},
+			flowkit.UpdateExistingContract(update),
+		)
+		if err != nil {
+			w.logger.Info(fmt.Sprintf(
+				"%s Failed to deploy contract %s: %s",
+				output.ErrorEmoji(),
+				contract.Name,
+				err.Error(),
+			))
+			failed++
+			continue
+		}
+
+		planned.transactionID = txID.Hex()
+		w.logger.Info(fmt.Sprintf("deployed %s contract", contract.Name))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed deploying %d of %d contracts", failed, len(plan))
+	}
+
+	return nil
+}
+
+func serializeDeploymentPlan(plan []*plannedContract) []any {
+	values := make([]any, 0, len(plan))
+	for _, planned := range plan {
+		values = append(values, map[string]any{
+			"name":          planned.contract.Name,
+			"account":       planned.contract.AccountName,
+			"address":       planned.contract.AccountAddress.HexWithPrefix(),
+			"location":      planned.contract.Location(),
+			"imports":       planned.imports,
+			"action":        string(planned.action),
+			"transactionId": planned.transactionID,
+		})
+	}
+	return values
+}
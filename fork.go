@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/onflow/flow-go-sdk"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flowkit/v2/config"
+	jsFlow "github.com/onflowser/flow-cli-wasm/js"
+	"github.com/onflowser/flow-cli-wasm/store"
+)
+
+var forkChainIDs = map[string]flowgo.ChainID{
+	config.MainnetNetwork.Name:    flowgo.Mainnet,
+	config.TestnetNetwork.Name:    flowgo.Testnet,
+	config.PreviewnetNetwork.Name: flowgo.Previewnet,
+}
+
+// forkNetwork makes the emulator start from the state of a remote network (e.g. "mainnet") at the fork height.
+// Account registers are lazily fetched through the external gateway and cached in the store.
+func forkNetwork(
+	emulatorStore *store.Store,
+	externalGateways map[string]*jsFlow.ExternalGateway,
+	emulatorConfig EmulatorConfig,
+) error {
+	remote, ok := externalGateways[emulatorConfig.Fork]
+	if !ok {
+		return fmt.Errorf("gateway for network %s not found", emulatorConfig.Fork)
+	}
+	if !remote.SupportsRegisterValues() {
+		return fmt.Errorf("gateway for network %s doesn't implement getRegisterValue, which is required for forking", emulatorConfig.Fork)
+	}
+
+	ctx := context.Background()
+
+	var block *sdk.Block
+	var err error
+	if emulatorConfig.ForkHeight == 0 {
+		block, err = remote.GetLatestBlock(ctx)
+	} else {
+		block, err = remote.GetBlockByHeight(ctx, emulatorConfig.ForkHeight)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the fork block: %w", err)
+	}
+
+	payload := flowgo.EmptyPayload()
+
+	// The emulator doesn't bootstrap the chain if the store already has a block,
+	// so the synthetic fork block replaces the genesis block and all accounts (including the service account)
+	// come from the forked network. The configured service key therefore doesn't control the service account,
+	// which can still propose and pay for transactions as long as transaction validation is disabled.
+	//
+	// Block ID can't be preserved, since the external gateway doesn't provide all header fields.
+	return emulatorStore.Fork(ctx, remote, &flowgo.Block{
+		Header: &flowgo.Header{
+			ChainID:   forkChainIDs[emulatorConfig.Fork],
+			ParentID:  flowgo.Identifier(block.ParentID),
+			Height:    block.Height,
+			Timestamp: block.Timestamp,
+		},
+		Payload: &payload,
+	})
+}
@@ -7,9 +7,11 @@ toolchain go1.22.3
 require (
 	github.com/onflow/cadence v1.0.0-preview.35
 	github.com/onflow/flow-emulator v1.0.0-preview.32
+	github.com/onflow/flow-go v0.35.14-crescendo-preview.27.0.20240626210601-604590f19db9
 	github.com/onflow/flow-go-sdk v1.0.0-preview.37
 	github.com/onflow/flowkit/v2 v2.0.0-stable-cadence-alpha.24.0.20240618003932-da49a83e32a8
 	github.com/rs/zerolog v1.33.0
+	google.golang.org/grpc v1.63.2
 )
 
 require (
@@ -136,7 +138,6 @@ require (
 	github.com/onflow/flow-core-contracts/lib/go/templates v1.3.0 // indirect
 	github.com/onflow/flow-ft/lib/go/contracts v1.0.0 // indirect
 	github.com/onflow/flow-ft/lib/go/templates v1.0.0 // indirect
-	github.com/onflow/flow-go v0.35.14-crescendo-preview.27.0.20240626210601-604590f19db9 // indirect
 	github.com/onflow/flow-nft/lib/go/contracts v1.2.1 // indirect
 	github.com/onflow/flow-nft/lib/go/templates v1.2.0 // indirect
 	github.com/onflow/flow/protobuf/go/flow v0.4.4 // indirect
@@ -212,7 +213,6 @@ require (
 	google.golang.org/genproto v0.0.0-20240227224415-6ceb2ff114de // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20240227224415-6ceb2ff114de // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240227224415-6ceb2ff114de // indirect
-	google.golang.org/grpc v1.63.2 // indirect
 	google.golang.org/protobuf v1.33.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -2,11 +2,20 @@ package js
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
 	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flowkit/v2/gateway"
+	"strconv"
+	"strings"
 	"syscall/js"
+	"time"
 )
 
 // ExternalGateway exposes access API to external networks
@@ -20,6 +29,18 @@ func NewExternalGateway(target js.Value) *ExternalGateway {
 	return &ExternalGateway{target: target}
 }
 
+// AccountKeyJSON is the account key format exchanged with the JS gateway,
+// which matches the key format used by FCL.
+type AccountKeyJSON struct {
+	Index          int    `json:"index"`
+	PublicKey      string `json:"publicKey"`
+	SignAlgoString string `json:"signAlgoString"`
+	HashAlgoString string `json:"hashAlgoString"`
+	Weight         int    `json:"weight"`
+	SequenceNumber uint64 `json:"sequenceNumber"`
+	Revoked        bool   `json:"revoked"`
+}
+
 func (g *ExternalGateway) GetAccount(ctx context.Context, address sdk.Address) (*sdk.Account, error) {
 	value, err := parseResult(resolvePromise(g.target.Call("getAccount", address.Hex())))
 
@@ -27,11 +48,18 @@ func (g *ExternalGateway) GetAccount(ctx context.Context, address sdk.Address) (
 		return nil, err
 	}
 
+	// Balance is passed as a string, since it may not fit into a JS number
+	balance, err := strconv.ParseUint(value.Get("balance").String(), 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid account balance: %w", err)
+	}
+
 	var contracts map[string]string
 	err = json.Unmarshal([]byte(value.Get("contracts").String()), &contracts)
 
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("invalid account contracts: %w", err)
 	}
 
 	deserializedContracts := make(map[string][]byte)
@@ -40,96 +68,697 @@ func (g *ExternalGateway) GetAccount(ctx context.Context, address sdk.Address) (
 		deserializedContracts[key] = []byte(value)
 	}
 
+	var keys []AccountKeyJSON
+	err = json.Unmarshal([]byte(value.Get("keys").String()), &keys)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid account keys: %w", err)
+	}
+
+	deserializedKeys, err := deserializeExternalAccountKeys(keys)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &sdk.Account{
 		Address:   sdk.HexToAddress(value.Get("address").String()),
-		Balance:   uint64(value.Get("balance").Int()),
+		Balance:   balance,
+		Code:      []byte(value.Get("code").String()),
+		Keys:      deserializedKeys,
 		Contracts: deserializedContracts,
 	}, nil
 }
 
+func deserializeExternalAccountKeys(keys []AccountKeyJSON) ([]*sdk.AccountKey, error) {
+	deserializedKeys := make([]*sdk.AccountKey, 0)
+	for _, key := range keys {
+		sigAlgo := crypto.StringToSignatureAlgorithm(key.SignAlgoString)
+
+		if sigAlgo == crypto.UnknownSignatureAlgorithm {
+			return nil, fmt.Errorf("unknown signature algorithm %s for key %d", key.SignAlgoString, key.Index)
+		}
+
+		hashAlgo := crypto.StringToHashAlgorithm(key.HashAlgoString)
+
+		if hashAlgo == crypto.UnknownHashAlgorithm {
+			return nil, fmt.Errorf("unknown hash algorithm %s for key %d", key.HashAlgoString, key.Index)
+		}
+
+		publicKey, err := crypto.DecodePublicKeyHex(sigAlgo, strings.TrimPrefix(key.PublicKey, "0x"))
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %d: %w", key.Index, err)
+		}
+
+		deserializedKeys = append(deserializedKeys, &sdk.AccountKey{
+			Index:          key.Index,
+			PublicKey:      publicKey,
+			SigAlgo:        sigAlgo,
+			HashAlgo:       hashAlgo,
+			Weight:         key.Weight,
+			SequenceNumber: key.SequenceNumber,
+			Revoked:        key.Revoked,
+		})
+	}
+	return deserializedKeys, nil
+}
+
+// TransactionJSON is the transaction format exchanged with the JS gateway.
+// Arguments are JSON-Cadence encoded and signatures are hex encoded.
+type TransactionJSON struct {
+	Script             string                     `json:"script"`
+	Arguments          []string                   `json:"arguments"`
+	ReferenceBlockID   string                     `json:"referenceBlockId"`
+	GasLimit           uint64                     `json:"gasLimit"`
+	ProposalKey        ProposalKeyJSON            `json:"proposalKey"`
+	Payer              string                     `json:"payer"`
+	Authorizers        []string                   `json:"authorizers"`
+	PayloadSignatures  []TransactionSignatureJSON `json:"payloadSignatures"`
+	EnvelopeSignatures []TransactionSignatureJSON `json:"envelopeSignatures"`
+}
+
+type ProposalKeyJSON struct {
+	Address        string `json:"address"`
+	KeyIndex       int    `json:"keyIndex"`
+	SequenceNumber uint64 `json:"sequenceNumber"`
+}
+
+type TransactionSignatureJSON struct {
+	Address   string `json:"address"`
+	KeyIndex  int    `json:"keyIndex"`
+	Signature string `json:"signature"`
+}
+
+// TransactionResultJSON is the transaction result format exchanged with the JS gateway.
+// Status uses the same numeric values as sdk.TransactionStatus.
+type TransactionResultJSON struct {
+	Status        int         `json:"status"`
+	ErrorMessage  string      `json:"errorMessage"`
+	Events        []EventJSON `json:"events"`
+	BlockID       string      `json:"blockId"`
+	BlockHeight   uint64      `json:"blockHeight"`
+	TransactionID string      `json:"transactionId"`
+	CollectionID  string      `json:"collectionId"`
+}
+
+type EventJSON struct {
+	Type             string `json:"type"`
+	TransactionID    string `json:"transactionId"`
+	TransactionIndex int    `json:"transactionIndex"`
+	EventIndex       int    `json:"eventIndex"`
+	// JSON-Cadence encoded event value
+	Payload string `json:"payload"`
+}
+
 func (g *ExternalGateway) SendSignedTransaction(ctx context.Context, transaction *sdk.Transaction) (*sdk.Transaction, error) {
-	//TODO implement me
-	panic("implement me")
+	request, err := json.Marshal(serializeExternalTransaction(transaction))
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseResult(resolvePromise(g.target.Call("sendSignedTransaction", string(request))))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if id := sdk.HexToID(value.String()); id != transaction.ID() {
+		return nil, fmt.Errorf("submitted transaction ID %s does not match computed ID %s", id, transaction.ID())
+	}
+
+	return transaction, nil
 }
 
-func (g *ExternalGateway) GetTransaction(ctx context.Context, identifier sdk.Identifier) (*sdk.Transaction, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) GetTransaction(ctx context.Context, id sdk.Identifier) (*sdk.Transaction, error) {
+	value, err := parseResult(resolvePromise(g.target.Call("getTransaction", id.Hex())))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var transaction TransactionJSON
+	err = json.Unmarshal([]byte(value.String()), &transaction)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deserializeExternalTransaction(transaction)
 }
 
+// GetTransactionResultsByBlockID fetches results of the block transactions one by one,
+// since the REST API doesn't have an endpoint for all results of a block.
 func (g *ExternalGateway) GetTransactionResultsByBlockID(ctx context.Context, blockID sdk.Identifier) ([]*sdk.TransactionResult, error) {
-	//TODO implement me
-	panic("implement me")
+	transactions, err := g.GetTransactionsByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*sdk.TransactionResult, 0, len(transactions))
+	for _, transaction := range transactions {
+		result, err := g.getTransactionResult(transaction.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get result of transaction %s: %w", transaction.ID(), err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetTransactionResult polls the transaction result every second until it's sealed if waitSeal is true.
+func (g *ExternalGateway) GetTransactionResult(ctx context.Context, id sdk.Identifier, waitSeal bool) (*sdk.TransactionResult, error) {
+	for {
+		result, err := g.getTransactionResult(id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Expired transactions will never be sealed
+		if !waitSeal || result.Status == sdk.TransactionStatusSealed || result.Status == sdk.TransactionStatusExpired {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (g *ExternalGateway) getTransactionResult(id sdk.Identifier) (*sdk.TransactionResult, error) {
+	value, err := parseResult(resolvePromise(g.target.Call("getTransactionResult", id.Hex())))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result TransactionResultJSON
+	err = json.Unmarshal([]byte(value.String()), &result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deserializeExternalTransactionResult(result)
+}
+
+func serializeExternalTransaction(tx *sdk.Transaction) TransactionJSON {
+	serializedArguments := make([]string, 0)
+	for _, argument := range tx.Arguments {
+		serializedArguments = append(serializedArguments, string(argument))
+	}
+
+	serializedAuthorizers := make([]string, 0)
+	for _, authorizer := range tx.Authorizers {
+		serializedAuthorizers = append(serializedAuthorizers, authorizer.Hex())
+	}
+
+	return TransactionJSON{
+		Script:           string(tx.Script),
+		Arguments:        serializedArguments,
+		ReferenceBlockID: tx.ReferenceBlockID.Hex(),
+		GasLimit:         tx.GasLimit,
+		ProposalKey: ProposalKeyJSON{
+			Address:        tx.ProposalKey.Address.Hex(),
+			KeyIndex:       tx.ProposalKey.KeyIndex,
+			SequenceNumber: tx.ProposalKey.SequenceNumber,
+		},
+		Payer:              tx.Payer.Hex(),
+		Authorizers:        serializedAuthorizers,
+		PayloadSignatures:  serializeExternalSignatures(tx.PayloadSignatures),
+		EnvelopeSignatures: serializeExternalSignatures(tx.EnvelopeSignatures),
+	}
+}
+
+func serializeExternalSignatures(signatures []sdk.TransactionSignature) []TransactionSignatureJSON {
+	serializedSignatures := make([]TransactionSignatureJSON, 0)
+	for _, signature := range signatures {
+		serializedSignatures = append(serializedSignatures, TransactionSignatureJSON{
+			Address:   signature.Address.Hex(),
+			KeyIndex:  signature.KeyIndex,
+			Signature: hex.EncodeToString(signature.Signature),
+		})
+	}
+	return serializedSignatures
+}
+
+func deserializeExternalTransaction(transaction TransactionJSON) (*sdk.Transaction, error) {
+	tx := sdk.NewTransaction().
+		SetScript([]byte(transaction.Script)).
+		SetReferenceBlockID(sdk.HexToID(transaction.ReferenceBlockID)).
+		SetComputeLimit(transaction.GasLimit).
+		SetProposalKey(
+			sdk.HexToAddress(transaction.ProposalKey.Address),
+			transaction.ProposalKey.KeyIndex,
+			transaction.ProposalKey.SequenceNumber,
+		).
+		SetPayer(sdk.HexToAddress(transaction.Payer))
+
+	for _, argument := range transaction.Arguments {
+		tx.AddRawArgument([]byte(argument))
+	}
+
+	for _, authorizer := range transaction.Authorizers {
+		tx.AddAuthorizer(sdk.HexToAddress(authorizer))
+	}
+
+	// Signatures must be added after all signers are known, so that signer indexes are computed correctly.
+	for _, signature := range transaction.PayloadSignatures {
+		decodedSignature, err := hex.DecodeString(strings.TrimPrefix(signature.Signature, "0x"))
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload signature: %w", err)
+		}
+
+		tx.AddPayloadSignature(sdk.HexToAddress(signature.Address), signature.KeyIndex, decodedSignature)
+	}
+
+	for _, signature := range transaction.EnvelopeSignatures {
+		decodedSignature, err := hex.DecodeString(strings.TrimPrefix(signature.Signature, "0x"))
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid envelope signature: %w", err)
+		}
+
+		tx.AddEnvelopeSignature(sdk.HexToAddress(signature.Address), signature.KeyIndex, decodedSignature)
+	}
+
+	return tx, nil
+}
+
+func deserializeExternalTransactionResult(result TransactionResultJSON) (*sdk.TransactionResult, error) {
+	events, err := deserializeExternalEvents(result.Events)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var resultErr error
+	if result.ErrorMessage != "" {
+		resultErr = errors.New(result.ErrorMessage)
+	}
+
+	return &sdk.TransactionResult{
+		Status:        sdk.TransactionStatus(result.Status),
+		Error:         resultErr,
+		Events:        events,
+		BlockID:       sdk.HexToID(result.BlockID),
+		BlockHeight:   result.BlockHeight,
+		TransactionID: sdk.HexToID(result.TransactionID),
+		CollectionID:  sdk.HexToID(result.CollectionID),
+	}, nil
+}
+
+func deserializeExternalEvents(events []EventJSON) ([]sdk.Event, error) {
+	deserializedEvents := make([]sdk.Event, 0)
+	for _, event := range events {
+		value, err := jsoncdc.Decode(nil, []byte(event.Payload))
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode event %s: %w", event.Type, err)
+		}
+
+		eventValue, ok := value.(cadence.Event)
+
+		if !ok {
+			return nil, fmt.Errorf("expected event value for %s, got %s", event.Type, value.Type().ID())
+		}
+
+		deserializedEvents = append(deserializedEvents, sdk.Event{
+			Type:             event.Type,
+			TransactionID:    sdk.HexToID(event.TransactionID),
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+			Value:            eventValue,
+			Payload:          []byte(event.Payload),
+		})
+	}
+	return deserializedEvents, nil
+}
+
+func (g *ExternalGateway) GetTransactionsByBlockID(ctx context.Context, blockID sdk.Identifier) ([]*sdk.Transaction, error) {
+	value, err := parseResult(resolvePromise(g.target.Call("getTransactionsByBlockId", blockID.Hex())))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []TransactionJSON
+	err = json.Unmarshal([]byte(value.String()), &transactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deserializedTransactions := make([]*sdk.Transaction, 0)
+	for _, transaction := range transactions {
+		tx, err := deserializeExternalTransaction(transaction)
+
+		if err != nil {
+			return nil, err
+		}
+
+		deserializedTransactions = append(deserializedTransactions, tx)
+	}
+
+	return deserializedTransactions, nil
 }
 
-func (g *ExternalGateway) GetTransactionResult(ctx context.Context, identifier sdk.Identifier, b bool) (*sdk.TransactionResult, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	argsJSON, err := serializeArguments(arguments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := json.Marshal(ExecuteScriptRequest{
+		Script:   string(script),
+		ArgsJSON: argsJSON,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseResult(resolvePromise(g.target.Call("executeScript", string(request))))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return jsoncdc.Decode(nil, []byte(value.String()))
 }
 
-func (g *ExternalGateway) GetTransactionsByBlockID(ctx context.Context, identifier sdk.Identifier) ([]*sdk.Transaction, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) ExecuteScriptAtHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	argsJSON, err := serializeArguments(arguments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := json.Marshal(ExecuteScriptAtHeightRequest{
+		ExecuteScriptRequest: ExecuteScriptRequest{
+			Script:   string(script),
+			ArgsJSON: argsJSON,
+		},
+		Height: height,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseResult(resolvePromise(g.target.Call("executeScriptAtHeight", string(request))))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return jsoncdc.Decode(nil, []byte(value.String()))
 }
 
-func (g *ExternalGateway) ExecuteScript(ctx context.Context, bytes []byte, values []cadence.Value) (cadence.Value, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) ExecuteScriptAtID(ctx context.Context, script []byte, arguments []cadence.Value, id sdk.Identifier) (cadence.Value, error) {
+	argsJSON, err := serializeArguments(arguments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := json.Marshal(ExecuteScriptAtID{
+		ExecuteScriptRequest: ExecuteScriptRequest{
+			Script:   string(script),
+			ArgsJSON: argsJSON,
+		},
+		ID: id.Hex(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseResult(resolvePromise(g.target.Call("executeScriptAtId", string(request))))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return jsoncdc.Decode(nil, []byte(value.String()))
 }
 
-func (g *ExternalGateway) ExecuteScriptAtHeight(ctx context.Context, bytes []byte, values []cadence.Value, u uint64) (cadence.Value, error) {
-	//TODO implement me
-	panic("implement me")
+// serializeArguments encodes the values to a JSON array of JSON-Cadence values,
+// which is the format expected by ExecuteScriptRequest.ArgsJSON.
+func serializeArguments(values []cadence.Value) (string, error) {
+	encodedValues := make([]json.RawMessage, 0)
+	for _, value := range values {
+		encodedValue, err := jsoncdc.Encode(value)
+
+		if err != nil {
+			return "", err
+		}
+
+		encodedValues = append(encodedValues, encodedValue)
+	}
+
+	result, err := json.Marshal(encodedValues)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// BlockJSON is the block format exchanged with the JS gateway.
+type BlockJSON struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parentId"`
+	Height   uint64 `json:"height"`
+	// RFC 3339 formatted timestamp
+	Timestamp            string                    `json:"timestamp"`
+	CollectionGuarantees []CollectionGuaranteeJSON `json:"collectionGuarantees"`
+	BlockSeals           []BlockSealJSON           `json:"blockSeals"`
+}
+
+type CollectionGuaranteeJSON struct {
+	CollectionID string `json:"collectionId"`
+}
+
+type BlockSealJSON struct {
+	BlockID            string `json:"blockId"`
+	ExecutionReceiptID string `json:"executionReceiptId"`
+}
+
+// CollectionJSON is the collection format exchanged with the JS gateway.
+type CollectionJSON struct {
+	ID             string   `json:"id"`
+	TransactionIDs []string `json:"transactionIds"`
 }
 
-func (g *ExternalGateway) ExecuteScriptAtID(ctx context.Context, bytes []byte, values []cadence.Value, identifier sdk.Identifier) (cadence.Value, error) {
-	//TODO implement me
-	panic("implement me")
+// BlockEventsJSON is the format of events grouped by block exchanged with the JS gateway.
+type BlockEventsJSON struct {
+	BlockID     string `json:"blockId"`
+	BlockHeight uint64 `json:"blockHeight"`
+	// RFC 3339 formatted timestamp
+	BlockTimestamp string      `json:"blockTimestamp"`
+	Events         []EventJSON `json:"events"`
 }
 
 func (g *ExternalGateway) GetLatestBlock(ctx context.Context) (*sdk.Block, error) {
-	//TODO implement me
-	panic("implement me")
+	return g.getBlock(g.target.Call("getLatestBlock"))
+}
+
+func (g *ExternalGateway) GetBlockByHeight(ctx context.Context, height uint64) (*sdk.Block, error) {
+	return g.getBlock(g.target.Call("getBlockByHeight", height))
 }
 
-func (g *ExternalGateway) GetBlockByHeight(ctx context.Context, u uint64) (*sdk.Block, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) GetBlockByID(ctx context.Context, id sdk.Identifier) (*sdk.Block, error) {
+	return g.getBlock(g.target.Call("getBlockById", id.Hex()))
 }
 
-func (g *ExternalGateway) GetBlockByID(ctx context.Context, identifier sdk.Identifier) (*sdk.Block, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) getBlock(promise js.Value) (*sdk.Block, error) {
+	value, err := parseResult(resolvePromise(promise))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var block BlockJSON
+	err = json.Unmarshal([]byte(value.String()), &block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deserializeExternalBlock(block)
 }
 
-func (g *ExternalGateway) GetEvents(ctx context.Context, s string, u uint64, u2 uint64) ([]sdk.BlockEvents, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) GetEvents(ctx context.Context, eventType string, startHeight uint64, endHeight uint64) ([]sdk.BlockEvents, error) {
+	value, err := parseResult(resolvePromise(g.target.Call("getEvents", eventType, startHeight, endHeight)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var blockEvents []BlockEventsJSON
+	err = json.Unmarshal([]byte(value.String()), &blockEvents)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deserializedBlockEvents := make([]sdk.BlockEvents, 0)
+	for _, value := range blockEvents {
+		timestamp, err := time.Parse(time.RFC3339Nano, value.BlockTimestamp)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid block timestamp: %w", err)
+		}
+
+		events, err := deserializeExternalEvents(value.Events)
+
+		if err != nil {
+			return nil, err
+		}
+
+		deserializedBlockEvents = append(deserializedBlockEvents, sdk.BlockEvents{
+			BlockID:        sdk.HexToID(value.BlockID),
+			Height:         value.BlockHeight,
+			BlockTimestamp: timestamp,
+			Events:         events,
+		})
+	}
+
+	return deserializedBlockEvents, nil
 }
 
-func (g *ExternalGateway) GetCollection(ctx context.Context, identifier sdk.Identifier) (*sdk.Collection, error) {
-	//TODO implement me
-	panic("implement me")
+func (g *ExternalGateway) GetCollection(ctx context.Context, id sdk.Identifier) (*sdk.Collection, error) {
+	value, err := parseResult(resolvePromise(g.target.Call("getCollection", id.Hex())))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var collection CollectionJSON
+	err = json.Unmarshal([]byte(value.String()), &collection)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactionIDs := make([]sdk.Identifier, 0)
+	for _, transactionID := range collection.TransactionIDs {
+		transactionIDs = append(transactionIDs, sdk.HexToID(transactionID))
+	}
+
+	return &sdk.Collection{TransactionIDs: transactionIDs}, nil
 }
 
+func deserializeExternalBlock(block BlockJSON) (*sdk.Block, error) {
+	timestamp, err := time.Parse(time.RFC3339Nano, block.Timestamp)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid block timestamp: %w", err)
+	}
+
+	collectionGuarantees := make([]*sdk.CollectionGuarantee, 0)
+	for _, value := range block.CollectionGuarantees {
+		collectionGuarantees = append(collectionGuarantees, &sdk.CollectionGuarantee{
+			CollectionID: sdk.HexToID(value.CollectionID),
+		})
+	}
+
+	seals := make([]*sdk.BlockSeal, 0)
+	for _, value := range block.BlockSeals {
+		seals = append(seals, &sdk.BlockSeal{
+			BlockID:            sdk.HexToID(value.BlockID),
+			ExecutionReceiptID: sdk.HexToID(value.ExecutionReceiptID),
+		})
+	}
+
+	return &sdk.Block{
+		BlockHeader: sdk.BlockHeader{
+			ID:        sdk.HexToID(block.ID),
+			ParentID:  sdk.HexToID(block.ParentID),
+			Height:    block.Height,
+			Timestamp: timestamp,
+		},
+		BlockPayload: sdk.BlockPayload{
+			CollectionGuarantees: collectionGuarantees,
+			Seals:                seals,
+		},
+	}, nil
+}
+
+// SupportsRegisterValues reports whether the JS gateway implements the optional getRegisterValue method.
+// Register values aren't available through the Flow REST API (see RestGateway),
+// so forking requires a gateway backed by the Execution Data API.
+func (g *ExternalGateway) SupportsRegisterValues() bool {
+	return g.target.Get("getRegisterValue").Type() == js.TypeFunction
+}
+
+// GetRegisterValue is used for forking the network (see store.Remote).
+func (g *ExternalGateway) GetRegisterValue(ctx context.Context, height uint64, id flowgo.RegisterID) ([]byte, error) {
+	value, err := parseResult(resolvePromise(g.target.Call(
+		"getRegisterValue",
+		height,
+		hex.EncodeToString([]byte(id.Owner)),
+		hex.EncodeToString([]byte(id.Key)),
+	)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if value.IsNull() {
+		return []byte{}, nil
+	}
+
+	return hex.DecodeString(value.String())
+}
+
+// GetLatestProtocolStateSnapshot isn't supported, since protocol state snapshots are only exposed by the gRPC API.
 func (g *ExternalGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
-	//TODO implement me
-	panic("implement me")
+	return nil, fmt.Errorf("protocol state snapshots are not supported by external gateways")
 }
 
 func (g *ExternalGateway) Ping() error {
-	//TODO implement me
-	panic("implement me")
+	_, err := parseResult(resolvePromise(g.target.Call("ping")))
+	return err
 }
 
+const (
+	waitServerInitialDelay = 250 * time.Millisecond
+	waitServerMaxDelay     = 5 * time.Second
+)
+
+// WaitServer pings the gateway with exponential backoff until it's reachable or the context is done.
 func (g *ExternalGateway) WaitServer(ctx context.Context) error {
-	//TODO implement me
-	panic("implement me")
+	delay := waitServerInitialDelay
+	for {
+		err := g.Ping()
+
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gateway not reachable: %w (last error: %w)", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, waitServerMaxDelay)
+	}
 }
 
 func (g *ExternalGateway) SecureConnection() bool {
-	//TODO implement me
-	panic("implement me")
+	return g.target.Call("isSecureConnection").Bool()
 }
 
 var _ gateway.Gateway = &ExternalGateway{}
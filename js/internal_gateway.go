@@ -2,14 +2,23 @@ package js
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-emulator/types"
 	sdk "github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/crypto"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flowkit/v2/arguments"
-	"github.com/onflow/flowkit/v2/gateway"
+	"github.com/onflowser/flow-cli-wasm/blockchain"
+	"regexp"
+	"strconv"
+	"strings"
 	"syscall/js"
+	"time"
 )
 
 // Mapping as defined in https://github.com/onflow/fcl-js/blob/9c7873140015c9d1e28712aed93c56654f656639/packages/transport-grpc/src/send-get-account.js#L16-L28
@@ -29,11 +38,11 @@ var signAlgoToJsIndex = map[crypto.SignatureAlgorithm]int{
 }
 
 type InternalGateway struct {
-	emulator *gateway.EmulatorGateway
+	emulator *blockchain.Gateway
 	target   js.Value
 }
 
-func NewInternalGateway(emulator *gateway.EmulatorGateway) *InternalGateway {
+func NewInternalGateway(emulator *blockchain.Gateway) *InternalGateway {
 	target := js.Global().Get("Object").New()
 
 	gtw := &InternalGateway{
@@ -41,20 +50,25 @@ func NewInternalGateway(emulator *gateway.EmulatorGateway) *InternalGateway {
 		target,
 	}
 
-	target.Set("getAccount", js.FuncOf(gtw.getAccount))
-	target.Set("getLatestBlock", js.FuncOf(gtw.getLatestBlock))
-	target.Set("getBlockById", js.FuncOf(gtw.getBlockByID))
-	target.Set("getBlockByHeight", js.FuncOf(gtw.getBlockByHeight))
-	target.Set("getTransactionsByBlockId", js.FuncOf(gtw.getTransactionsByBlockID))
-	target.Set("getTransaction", js.FuncOf(gtw.getTransaction))
-	target.Set("getCollection", js.FuncOf(gtw.getCollection))
-	target.Set("sendSignedTransaction", js.FuncOf(gtw.sendSignedTransaction))
-	target.Set("getNetworkParameters", js.FuncOf(gtw.getNetworkParameters))
-	target.Set("getTransactionResultsByBlockId", js.FuncOf(gtw.getTransactionResultsByBlockID))
-	target.Set("getTransactionResult", js.FuncOf(gtw.getTransactionResult))
-	target.Set("executeScript", js.FuncOf(gtw.executeScript))
-	target.Set("executeScriptAtHeight", js.FuncOf(gtw.executeScriptAtHeight))
-	target.Set("executeScriptAtId", js.FuncOf(gtw.executeScriptAtID))
+	target.Set("getAccount", promiseFuncOf(gtw.getAccount))
+	target.Set("getLatestBlock", promiseFuncOf(gtw.getLatestBlock))
+	target.Set("getBlockById", promiseFuncOf(gtw.getBlockByID))
+	target.Set("getBlockByHeight", promiseFuncOf(gtw.getBlockByHeight))
+	target.Set("getTransactionsByBlockId", promiseFuncOf(gtw.getTransactionsByBlockID))
+	target.Set("getTransaction", promiseFuncOf(gtw.getTransaction))
+	target.Set("getCollection", promiseFuncOf(gtw.getCollection))
+	target.Set("sendSignedTransaction", promiseFuncOf(gtw.sendSignedTransaction))
+	target.Set("getNetworkParameters", promiseFuncOf(gtw.getNetworkParameters))
+	target.Set("getTransactionResultsByBlockId", promiseFuncOf(gtw.getTransactionResultsByBlockID))
+	target.Set("getTransactionResult", promiseFuncOf(gtw.getTransactionResult))
+	target.Set("executeScript", promiseFuncOf(gtw.executeScript))
+	target.Set("executeScriptAtHeight", promiseFuncOf(gtw.executeScriptAtHeight))
+	target.Set("executeScriptAtId", promiseFuncOf(gtw.executeScriptAtID))
+	target.Set("getEventsForHeightRange", promiseFuncOf(gtw.getEventsForHeightRange))
+	target.Set("getEventsForBlockIds", promiseFuncOf(gtw.getEventsForBlockIDs))
+	target.Set("subscribeBlocks", promiseFuncOf(gtw.subscribeBlocks))
+	target.Set("subscribeTransactionStatus", promiseFuncOf(gtw.subscribeTransactionStatus))
+	target.Set("subscribeEvents", promiseFuncOf(gtw.subscribeEvents))
 
 	return gtw
 }
@@ -63,14 +77,14 @@ func (g *InternalGateway) JsValue() js.Value {
 	return g.target
 }
 
-func (g *InternalGateway) getAccount(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getAccount(args []js.Value) (any, error) {
 	account, err := g.emulator.GetAccount(context.Background(), sdk.HexToAddress(args[0].String()))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return serializeAccount(account)
+	return serializeAccount(account), nil
 }
 
 func serializeAccount(account *sdk.Account) interface{} {
@@ -105,68 +119,123 @@ func serializeAccount(account *sdk.Account) interface{} {
 }
 
 type SendSignedTransactionRequest struct {
-	Script           string   `json:"script"`
-	ReferenceBlockID string   `json:"referenceBlockId"`
-	GasLimit         uint64   `json:"gasLimit"`
-	ArgsJSON         []string `json:"arguments"`
+	Script           string             `json:"script"`
+	ReferenceBlockID string             `json:"referenceBlockId"`
+	GasLimit         uint64             `json:"gasLimit"`
+	ArgsJSON         []string           `json:"arguments"`
+	ProposalKey      ProposalKeyRequest `json:"proposalKey"`
+	Payer            string             `json:"payer"`
+	Authorizers      []string           `json:"authorizers"`
+	// Signatures are only verified if transaction validation is enabled.
+	PayloadSignatures  []SignatureRequest `json:"payloadSignatures"`
+	EnvelopeSignatures []SignatureRequest `json:"envelopeSignatures"`
 }
 
-func (g *InternalGateway) sendSignedTransaction(this js.Value, args []js.Value) interface{} {
+// https://developers.flow.com/tools/clients/fcl-js/api#proposalkeyobject
+type ProposalKeyRequest struct {
+	Address        string `json:"address"`
+	KeyIndex       int    `json:"keyId"`
+	SequenceNumber uint64 `json:"sequenceNumber"`
+}
+
+// https://developers.flow.com/tools/clients/fcl-js/api#signableobject
+type SignatureRequest struct {
+	Address   string `json:"addr"`
+	KeyIndex  int    `json:"keyId"`
+	Signature string `json:"signature"` // Hex encoded
+}
+
+func (g *InternalGateway) sendSignedTransaction(args []js.Value) (any, error) {
 	var request SendSignedTransactionRequest
 	err := json.Unmarshal([]byte(args[0].String()), &request)
 
-	deserializedArgs := make([][]byte, 0)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if request.Payer == "" {
+		return nil, invalidRequestError(fmt.Errorf("payer is required"))
+	}
+
+	if request.ProposalKey.Address == "" {
+		return nil, invalidRequestError(fmt.Errorf("proposal key address is required"))
+	}
+
+	inputTx := sdk.NewTransaction().
+		SetScript([]byte(request.Script)).
+		SetReferenceBlockID(sdk.HexToID(request.ReferenceBlockID)).
+		SetComputeLimit(request.GasLimit).
+		SetProposalKey(
+			sdk.HexToAddress(request.ProposalKey.Address),
+			request.ProposalKey.KeyIndex,
+			request.ProposalKey.SequenceNumber,
+		).
+		SetPayer(sdk.HexToAddress(request.Payer))
+
 	for _, arg := range request.ArgsJSON {
-		deserializedArgs = append(deserializedArgs, []byte(arg))
+		inputTx.AddRawArgument([]byte(arg))
 	}
 
-	inputTx := &sdk.Transaction{
-		Script:             []byte(request.Script),
-		Arguments:          deserializedArgs,
-		ReferenceBlockID:   sdk.HexToID(request.ReferenceBlockID),
-		GasLimit:           request.GasLimit,
-		ProposalKey:        sdk.ProposalKey{},
-		Payer:              sdk.ServiceAddress(sdk.Emulator),
-		Authorizers:        nil,
-		PayloadSignatures:  nil,
-		EnvelopeSignatures: nil,
+	for _, authorizer := range request.Authorizers {
+		inputTx.AddAuthorizer(sdk.HexToAddress(authorizer))
+	}
+
+	// Signatures must be added after all signers (proposer, authorizers, payer) are set.
+	for _, sig := range request.PayloadSignatures {
+		signature, err := hex.DecodeString(strings.TrimPrefix(sig.Signature, "0x"))
+		if err != nil {
+			return nil, invalidRequestError(fmt.Errorf("invalid payload signature: %w", err))
+		}
+		inputTx.AddPayloadSignature(sdk.HexToAddress(sig.Address), sig.KeyIndex, signature)
+	}
+
+	for _, sig := range request.EnvelopeSignatures {
+		signature, err := hex.DecodeString(strings.TrimPrefix(sig.Signature, "0x"))
+		if err != nil {
+			return nil, invalidRequestError(fmt.Errorf("invalid envelope signature: %w", err))
+		}
+		inputTx.AddEnvelopeSignature(sdk.HexToAddress(sig.Address), sig.KeyIndex, signature)
 	}
 
 	outputTx, err := g.emulator.SendSignedTransaction(context.Background(), inputTx)
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return outputTx.ID().Hex()
+	return outputTx.ID().Hex(), nil
 }
 
-func (g *InternalGateway) getTransaction(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getTransaction(args []js.Value) (any, error) {
 	tx, err := g.emulator.GetTransaction(context.Background(), sdk.HexToID(args[0].String()))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return serializeTransaction(tx)
 }
 
-func (g *InternalGateway) getTransactionsByBlockID(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getTransactionsByBlockID(args []js.Value) (any, error) {
 	txs, err := g.emulator.GetTransactionsByBlockID(context.Background(), sdk.HexToID(args[0].String()))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	serializedTransactions := make([]interface{}, 0)
 	for _, tx := range txs {
-		serializedTransactions = append(serializedTransactions, serializeTransaction(tx))
+		serializedTransaction, err := serializeTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+		serializedTransactions = append(serializedTransactions, serializedTransaction)
 	}
 
-	return serializedTransactions
+	return serializedTransactions, nil
 }
 
-func serializeTransaction(tx *sdk.Transaction) map[string]interface{} {
+func serializeTransaction(tx *sdk.Transaction) (map[string]interface{}, error) {
 	// https://developers.flow.com/tools/clients/fcl-js/api#proposalkeyobject
 	serializedProposalKey := map[string]interface{}{
 		"address":        tx.ProposalKey.Address.Hex(),
@@ -189,6 +258,17 @@ func serializeTransaction(tx *sdk.Transaction) map[string]interface{} {
 		serializedPayloadSignatures = append(serializedPayloadSignatures, serializeSignature(value))
 	}
 
+	// Arguments are JSON-Cadence encoded, FCL expects them as parsed objects.
+	serializedArgs := make([]interface{}, 0)
+	for _, value := range tx.Arguments {
+		var arg interface{}
+		err := json.Unmarshal(value, &arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transaction argument: %w", err)
+		}
+		serializedArgs = append(serializedArgs, arg)
+	}
+
 	// https://developers.flow.com/tools/clients/fcl-js/api#transactionobject
 	return map[string]interface{}{
 		"authorizers":        serializedAuthorizers,
@@ -199,8 +279,8 @@ func serializeTransaction(tx *sdk.Transaction) map[string]interface{} {
 		"proposalKey":        serializedProposalKey,
 		"referenceBlockId":   tx.ReferenceBlockID.Hex(),
 		"script":             string(tx.Script),
-		"args":               []interface{}{}, // TODO: Implement
-	}
+		"args":               serializedArgs,
+	}, nil
 }
 
 func serializeSignature(sig sdk.TransactionSignature) interface{} {
@@ -208,70 +288,170 @@ func serializeSignature(sig sdk.TransactionSignature) interface{} {
 	return map[string]interface{}{
 		"addr":      sig.Address.Hex(),
 		"keyId":     sig.KeyIndex,
-		"signature": string(sig.Signature),
+		"signature": hex.EncodeToString(sig.Signature),
 	}
 }
 
-func (g *InternalGateway) getTransactionResultsByBlockID(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getTransactionResultsByBlockID(args []js.Value) (any, error) {
 	results, err := g.emulator.GetTransactionResultsByBlockID(context.Background(), sdk.HexToID(args[0].String()))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	serializedResults := make([]interface{}, 0)
 	for _, result := range results {
-		serializedResults = append(serializedResults, serializeTransactionResult(result))
+		serializedResult, err := g.serializeTransactionResult(result)
+		if err != nil {
+			return nil, err
+		}
+		serializedResults = append(serializedResults, serializedResult)
 	}
 
-	return serializedResults
+	return serializedResults, nil
 }
 
-func (g *InternalGateway) getTransactionResult(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getTransactionResult(args []js.Value) (any, error) {
 	result, err := g.emulator.GetTransactionResult(context.Background(), sdk.HexToID(args[0].String()), false)
 
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	return g.serializeTransactionResult(result)
+}
+
+// Matches the code prefix of FVM error messages (e.g. "[Error Code: 1101] cadence runtime error: ...").
+// Only used as a fallback for errors that were already converted to strings, see flowErrorCode.
+var errorCodeRegex = regexp.MustCompile(`\[Error Code: (\d+)\]`)
+
+// flowErrorCode returns the FVM error code, or zero if the error doesn't have one.
+// Emulator only keeps the message of failed transactions (see types.ExecutionError),
+// so the code is parsed from the message if the typed error isn't available.
+func flowErrorCode(err error, errorMessage string) int {
+	var codedErr fvmerrors.CodedError
+	if errors.As(err, &codedErr) {
+		return int(codedErr.Code())
+	}
+
+	if match := errorCodeRegex.FindStringSubmatch(errorMessage); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		return code
 	}
 
-	return serializeTransactionResult(result)
+	return 0
 }
 
-func serializeTransactionResult(result *sdk.TransactionResult) interface{} {
+func (g *InternalGateway) serializeTransactionResult(result *sdk.TransactionResult) (interface{}, error) {
+	// Pending and unknown transactions aren't included in a block yet.
+	blockTimestamp := ""
+	if result.BlockID != sdk.EmptyID {
+		block, err := g.emulator.GetBlockByID(context.Background(), result.BlockID)
+		if err != nil {
+			return nil, err
+		}
+		blockTimestamp = formatTimestamp(block.Timestamp)
+	}
+
 	serializedEvents := make([]interface{}, 0)
 	for _, event := range result.Events {
-		// https://developers.flow.com/tools/clients/fcl-js/api#event-object
-		serializedEvents = append(serializedEvents, map[string]interface{}{
-			"type":             event.Type,
-			"data":             event.Value.String(),
-			"blockId":          result.BlockID.Hex(),
-			"blockHeight":      result.BlockHeight,
-			"blockTimestamp":   "", // TODO: Implement
-			"transactionId":    event.TransactionID.Hex(),
-			"transactionIndex": event.TransactionIndex,
-			"eventIndex":       event.EventIndex,
-		})
+		serializedEvent, err := serializeEvent(event, result.BlockID, result.BlockHeight, blockTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		serializedEvents = append(serializedEvents, serializedEvent)
 	}
 
+	// FCL only distinguishes between success (0) and failure (1),
+	// the actual Flow error code is reported separately.
 	statusCode := 0
-	if result.Error != nil {
-		statusCode = 1
-	}
-
+	errorCode := 0
 	errorMessage := ""
 	if result.Error != nil {
+		statusCode = 1
 		errorMessage = result.Error.Error()
+		// Emulator wraps the original message with its own (always 1) status code.
+		var executionErr *types.ExecutionError
+		if errors.As(result.Error, &executionErr) {
+			errorMessage = executionErr.Message
+		}
+		errorCode = flowErrorCode(result.Error, errorMessage)
 	}
 
 	// https://developers.flow.com/tools/clients/fcl-js/api#transactionstatusobject
 	return map[string]interface{}{
-		"blockId":      result.BlockID.Hex(),
-		"events":       serializedEvents,
-		"status":       int(result.Status),
-		"statusString": result.Status.String(),
-		"errorMessage": errorMessage,
-		"statusCode":   statusCode,
+		"blockId":         result.BlockID.Hex(),
+		"events":          serializedEvents,
+		"status":          int(result.Status),
+		"statusString":    result.Status.String(),
+		"errorMessage":    errorMessage,
+		"statusCode":      statusCode,
+		"errorCode":       errorCode,
+		"computationUsed": g.emulator.ComputationUsed(result.TransactionID),
+	}, nil
+}
+
+func serializeEvent(event sdk.Event, blockID sdk.Identifier, blockHeight uint64, blockTimestamp string) (interface{}, error) {
+	payload, err := jsoncdc.Encode(event.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event payload: %w", err)
+	}
+
+	// https://developers.flow.com/tools/clients/fcl-js/api#event-object
+	return map[string]interface{}{
+		"type":             event.Type,
+		"blockId":          blockID.Hex(),
+		"blockHeight":      blockHeight,
+		"blockTimestamp":   blockTimestamp,
+		"transactionId":    event.TransactionID.Hex(),
+		"transactionIndex": event.TransactionIndex,
+		"eventIndex":       event.EventIndex,
+		// JSON-Cadence encoded event value, which FCL decodes into the event data
+		"payload": string(payload),
+	}, nil
+}
+
+func serializeBlockEvents(blockEvents []sdk.BlockEvents) (interface{}, error) {
+	serializedEvents := make([]interface{}, 0)
+	for _, block := range blockEvents {
+		for _, event := range block.Events {
+			serializedEvent, err := serializeEvent(event, block.BlockID, block.Height, formatTimestamp(block.BlockTimestamp))
+			if err != nil {
+				return nil, err
+			}
+			serializedEvents = append(serializedEvents, serializedEvent)
+		}
+	}
+
+	return serializedEvents, nil
+}
+
+func (g *InternalGateway) getEventsForHeightRange(args []js.Value) (any, error) {
+	eventType := args[0].String()
+	startHeight := uint64(args[1].Int())
+	endHeight := uint64(args[2].Int())
+
+	blockEvents, err := g.emulator.GetEvents(context.Background(), eventType, startHeight, endHeight)
+	if err != nil {
+		return nil, err
 	}
+
+	return serializeBlockEvents(blockEvents)
+}
+
+func (g *InternalGateway) getEventsForBlockIDs(args []js.Value) (any, error) {
+	eventType := args[0].String()
+	blockIDs := make([]sdk.Identifier, 0, args[1].Length())
+	for i := 0; i < args[1].Length(); i++ {
+		blockIDs = append(blockIDs, sdk.HexToID(args[1].Index(i).String()))
+	}
+
+	blockEvents, err := g.emulator.GetEventsForBlockIDs(context.Background(), eventType, blockIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return serializeBlockEvents(blockEvents)
 }
 
 type ExecuteScriptRequest struct {
@@ -279,12 +459,12 @@ type ExecuteScriptRequest struct {
 	ArgsJSON string `json:"arguments"`
 }
 
-func (g *InternalGateway) executeScript(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) executeScript(args []js.Value) (any, error) {
 	var request ExecuteScriptRequest
 	err := json.Unmarshal([]byte(args[0].String()), &request)
 
 	if err != nil {
-		panic(err)
+		return nil, invalidRequestError(err)
 	}
 
 	var cadenceArgs []cadence.Value
@@ -293,17 +473,23 @@ func (g *InternalGateway) executeScript(this js.Value, args []js.Value) interfac
 		cadenceArgs, err = arguments.ParseJSON(request.ArgsJSON)
 
 		if err != nil {
-			panic(err)
+			return nil, invalidRequestError(err)
 		}
 	}
 
 	result, err := g.emulator.ExecuteScript(context.Background(), []byte(request.Script), cadenceArgs)
 
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	encodedResult, err := jsoncdc.Encode(result)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return string(jsoncdc.MustEncode(result))
+	return string(encodedResult), nil
 }
 
 type ExecuteScriptAtHeightRequest struct {
@@ -311,12 +497,12 @@ type ExecuteScriptAtHeightRequest struct {
 	Height uint64 `json:"height"`
 }
 
-func (g *InternalGateway) executeScriptAtHeight(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) executeScriptAtHeight(args []js.Value) (any, error) {
 	var request ExecuteScriptAtHeightRequest
 	err := json.Unmarshal([]byte(args[0].String()), &request)
 
 	if err != nil {
-		panic(err)
+		return nil, invalidRequestError(err)
 	}
 
 	var cadenceArgs []cadence.Value
@@ -325,17 +511,23 @@ func (g *InternalGateway) executeScriptAtHeight(this js.Value, args []js.Value)
 		cadenceArgs, err = arguments.ParseJSON(request.ArgsJSON)
 
 		if err != nil {
-			panic(err)
+			return nil, invalidRequestError(err)
 		}
 	}
 
 	result, err := g.emulator.ExecuteScriptAtHeight(context.Background(), []byte(request.Script), cadenceArgs, request.Height)
 
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	encodedResult, err := jsoncdc.Encode(result)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return string(jsoncdc.MustEncode(result))
+	return string(encodedResult), nil
 }
 
 type ExecuteScriptAtID struct {
@@ -343,12 +535,12 @@ type ExecuteScriptAtID struct {
 	ID string `json:"id"`
 }
 
-func (g *InternalGateway) executeScriptAtID(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) executeScriptAtID(args []js.Value) (any, error) {
 	var request ExecuteScriptAtID
 	err := json.Unmarshal([]byte(args[0].String()), &request)
 
 	if err != nil {
-		panic(err)
+		return nil, invalidRequestError(err)
 	}
 
 	var cadenceArgs []cadence.Value
@@ -357,50 +549,70 @@ func (g *InternalGateway) executeScriptAtID(this js.Value, args []js.Value) inte
 		cadenceArgs, err = arguments.ParseJSON(request.ArgsJSON)
 
 		if err != nil {
-			panic(err)
+			return nil, invalidRequestError(err)
 		}
 	}
 
 	result, err := g.emulator.ExecuteScriptAtID(context.Background(), []byte(request.Script), cadenceArgs, sdk.HexToID(request.ID))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return string(jsoncdc.MustEncode(result))
+	encodedResult, err := jsoncdc.Encode(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(encodedResult), nil
 }
 
-func (g *InternalGateway) getLatestBlock(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getLatestBlock(args []js.Value) (any, error) {
 	block, err := g.emulator.GetLatestBlock(context.Background())
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return serializeBlock(block)
+	return g.serializeBlock(block)
 }
 
-func (g *InternalGateway) getBlockByHeight(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getBlockByHeight(args []js.Value) (any, error) {
 	block, err := g.emulator.GetBlockByHeight(context.Background(), uint64(args[0].Int()))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return serializeBlock(block)
+	return g.serializeBlock(block)
 }
 
-func (g *InternalGateway) getBlockByID(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getBlockByID(args []js.Value) (any, error) {
 	block, err := g.emulator.GetBlockByID(context.Background(), sdk.HexToID(args[0].String()))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return serializeBlock(block)
+	return g.serializeBlock(block)
 }
 
-func serializeBlock(block *sdk.Block) interface{} {
+func (g *InternalGateway) serializeBlock(block *sdk.Block) (interface{}, error) {
+	// SDK blocks don't include signatures, so they are read from the block header.
+	header, err := g.emulator.GetBlockHeaderByID(block.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emulator doesn't run consensus, so blocks are usually not signed.
+	serializedSignatures := make([]interface{}, 0)
+	for _, signature := range [][]byte{header.ProposerSigData, header.ParentVoterSigData} {
+		if len(signature) > 0 {
+			serializedSignatures = append(serializedSignatures, hex.EncodeToString(signature))
+		}
+	}
+
 	serializedCollectionGuarantees := make([]interface{}, 0)
 	for _, value := range block.CollectionGuarantees {
 		serializedCollectionGuarantees = append(serializedCollectionGuarantees, map[string]interface{}{
@@ -421,21 +633,27 @@ func serializeBlock(block *sdk.Block) interface{} {
 		"id":                   block.ID.Hex(),
 		"parentId":             block.ParentID.Hex(),
 		"height":               block.Height,
-		"timestamp":            block.Timestamp.String(),
+		"timestamp":            formatTimestamp(block.Timestamp),
 		"collectionGuarantees": serializedCollectionGuarantees,
 		"blockSeals":           serializedBlockSeals,
-		"signatures":           []interface{}{}, // TODO: Implement
-	}
+		"signatures":           serializedSignatures,
+		"parentVoterSignature": hex.EncodeToString(header.ParentVoterSigData),
+	}, nil
 }
 
-func (g *InternalGateway) getCollection(this js.Value, args []js.Value) interface{} {
+// formatTimestamp encodes timestamps in ISO-8601 (RFC 3339) format, as returned by the access node REST API.
+func formatTimestamp(timestamp time.Time) string {
+	return timestamp.UTC().Format(time.RFC3339Nano)
+}
+
+func (g *InternalGateway) getCollection(args []js.Value) (any, error) {
 	collection, err := g.emulator.GetCollection(context.Background(), sdk.HexToID(args[0].String()))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return serializeCollection(collection)
+	return serializeCollection(collection), nil
 }
 
 func serializeCollection(collection *sdk.Collection) interface{} {
@@ -451,8 +669,8 @@ func serializeCollection(collection *sdk.Collection) interface{} {
 	}
 }
 
-func (g *InternalGateway) getNetworkParameters(this js.Value, args []js.Value) interface{} {
+func (g *InternalGateway) getNetworkParameters(args []js.Value) (any, error) {
 	return map[string]interface{}{
-		"chainId": sdk.Emulator.String(),
-	}
+		"chainId": g.emulator.Blockchain().GetChain().ChainID().String(),
+	}, nil
 }
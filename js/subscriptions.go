@@ -0,0 +1,103 @@
+package js
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"syscall/js"
+
+	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflowser/flow-cli-wasm/blockchain"
+)
+
+type SubscribeEventsRequest struct {
+	EventTypes []string `json:"eventTypes"`
+	Addresses  []string `json:"addresses"`
+}
+
+// subscribeBlocks resolves with an unsubscribe function.
+// The callback receives each committed block.
+func (g *InternalGateway) subscribeBlocks(args []js.Value) (any, error) {
+	callback := args[0]
+
+	unsubscribe := g.emulator.SubscribeBlocks(func(block *sdk.Block) {
+		serializedBlock, err := g.serializeBlock(block)
+		if err != nil {
+			g.emulator.Logger().Error().Err(err).Msg("failed to serialize block")
+			return
+		}
+		callback.Invoke(serializedBlock)
+	})
+
+	return unsubscribeFuncOf(unsubscribe), nil
+}
+
+// subscribeTransactionStatus resolves with an unsubscribe function.
+// The callback receives the current transaction status and then each status change, until the transaction is sealed.
+func (g *InternalGateway) subscribeTransactionStatus(args []js.Value) (any, error) {
+	id := sdk.HexToID(args[0].String())
+	callback := args[1]
+
+	unsubscribe, err := g.emulator.SubscribeTransactionStatus(
+		context.Background(),
+		id,
+		func(result *sdk.TransactionResult) {
+			serializedResult, err := g.serializeTransactionResult(result)
+			if err != nil {
+				g.emulator.Logger().Error().Err(err).Msg("failed to serialize transaction result")
+				return
+			}
+			callback.Invoke(serializedResult)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return unsubscribeFuncOf(unsubscribe), nil
+}
+
+// subscribeEvents resolves with an unsubscribe function.
+// The first argument is JSON encoded SubscribeEventsRequest filter.
+func (g *InternalGateway) subscribeEvents(args []js.Value) (any, error) {
+	var request SubscribeEventsRequest
+	err := json.Unmarshal([]byte(args[0].String()), &request)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+	callback := args[1]
+
+	filter := blockchain.EventFilter{
+		EventTypes: request.EventTypes,
+		Addresses:  make([]sdk.Address, 0, len(request.Addresses)),
+	}
+	for _, address := range request.Addresses {
+		filter.Addresses = append(filter.Addresses, sdk.HexToAddress(address))
+	}
+
+	unsubscribe := g.emulator.SubscribeEvents(filter, func(event sdk.Event, block *sdk.Block) {
+		serializedEvent, err := serializeEvent(event, block.ID, block.Height, formatTimestamp(block.Timestamp))
+		if err != nil {
+			g.emulator.Logger().Error().Err(err).Msg("failed to serialize event")
+			return
+		}
+		callback.Invoke(serializedEvent)
+	})
+
+	return unsubscribeFuncOf(unsubscribe), nil
+}
+
+// unsubscribeFuncOf wraps the unsubscribe function so that it can be called from JS.
+// The function is released once it's called, so it must not be called again.
+func unsubscribeFuncOf(unsubscribe func()) js.Func {
+	var once sync.Once
+	var fn js.Func
+	fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		once.Do(func() {
+			unsubscribe()
+			fn.Release()
+		})
+		return nil
+	})
+	return fn
+}
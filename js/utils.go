@@ -1,8 +1,18 @@
 package js
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"syscall/js"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	cadenceErrors "github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 // AsyncWork handles executing blocking code in go routine with promises to avoid deadlocks
@@ -25,6 +35,9 @@ func AsyncWork(executor func() (js.Value, error)) js.Value {
 		return nil
 	})
 
+	// Promise constructor calls the handler synchronously, so it can be released right after
+	defer handler.Release()
+
 	// Create and return the Promise object
 	promiseConstructor := js.Global().Get("Promise")
 	return promiseConstructor.New(handler)
@@ -58,3 +71,140 @@ func parseResult(jsObject js.Value) (js.Value, error) {
 
 	return value, nil
 }
+
+type ErrorKind string
+
+const (
+	// ErrorKindInvalidRequest is used when the arguments passed from JS can't be decoded.
+	ErrorKindInvalidRequest ErrorKind = "invalid_request"
+	// ErrorKindCadence is used for Cadence checking and runtime errors.
+	ErrorKindCadence ErrorKind = "cadence"
+	// ErrorKindGateway is used for any other error returned by the gateway.
+	ErrorKindGateway ErrorKind = "gateway"
+)
+
+var errInvalidRequest = errors.New("invalid request")
+
+func invalidRequestError(err error) error {
+	return fmt.Errorf("%w: %w", errInvalidRequest, err)
+}
+
+// Matches the location line of pretty printed Cadence errors (e.g. " --> 0a2b...:3:10").
+// Only used as a fallback for errors that are passed as plain strings,
+// like the emulator's ExecutionError or errors from external gateways.
+var cadencePositionRegex = regexp.MustCompile(`-->\s+(\S+):(\d+):(\d+)`)
+
+// promiseFuncOf wraps the handler in a function that returns a Promise of a GoResult object from /ts-lib/src/go-interfaces.ts.
+// The handler is executed with AsyncWork, so it doesn't block the event loop and is allowed to await JS promises.
+// Errors (including panics) are returned in the "error" property, the returned Promise is never rejected.
+func promiseFuncOf(handler func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) any {
+		return AsyncWork(func() (js.Value, error) {
+			return safeExecute(handler, args), nil
+		})
+	})
+}
+
+// safeExecute also converts the result to a JS value, since the conversion panics for unsupported types.
+func safeExecute(handler func(args []js.Value) (any, error), args []js.Value) (result js.Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = js.ValueOf(serializeResult(nil, fmt.Errorf("%v", r)))
+		}
+	}()
+
+	return js.ValueOf(serializeResult(handler(args)))
+}
+
+func serializeResult(value any, err error) any {
+	if err != nil {
+		return map[string]any{
+			"value": nil,
+			"error": serializeError(err),
+		}
+	}
+
+	return map[string]any{
+		"value": value,
+		"error": nil,
+	}
+}
+
+func serializeError(err error) any {
+	kind := ErrorKindGateway
+	if errors.Is(err, errInvalidRequest) {
+		kind = ErrorKindInvalidRequest
+	}
+
+	var position any
+	if location, start, ok := cadenceErrorPosition(err, nil); ok {
+		kind = ErrorKindCadence
+		position = map[string]any{
+			"location": locationString(location),
+			"line":     start.Line,
+			"column":   start.Column,
+		}
+	} else if match := cadencePositionRegex.FindStringSubmatch(err.Error()); match != nil {
+		kind = ErrorKindCadence
+		line, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		position = map[string]any{
+			"location": match[1],
+			"line":     line,
+			"column":   column,
+		}
+	}
+
+	return map[string]any{
+		"kind":     string(kind),
+		"message":  err.Error(),
+		"position": position,
+	}
+}
+
+// cadenceErrorPosition returns the position of the first Cadence error in the error tree
+// and the location of the program it was reported in.
+func cadenceErrorPosition(err error, location common.Location) (common.Location, ast.Position, bool) {
+	switch typedErr := err.(type) {
+	case runtime.Error:
+		location = typedErr.Location
+	case *runtime.ParsingCheckingError:
+		location = typedErr.Location
+	case sema.CheckerError:
+		location = typedErr.Location
+	case interpreter.Error:
+		location = typedErr.Location
+	}
+
+	if positioned, ok := err.(ast.HasPosition); ok {
+		return location, positioned.StartPosition(), true
+	}
+
+	var children []error
+	switch typedErr := err.(type) {
+	case cadenceErrors.ParentError:
+		children = typedErr.ChildErrors()
+	case interface{ Unwrap() []error }:
+		children = typedErr.Unwrap()
+	case interface{ Unwrap() error }:
+		children = []error{typedErr.Unwrap()}
+	}
+
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if childLocation, start, ok := cadenceErrorPosition(child, location); ok {
+			return childLocation, start, true
+		}
+	}
+
+	return nil, ast.EmptyPosition, false
+}
+
+func locationString(location common.Location) string {
+	if location == nil {
+		return ""
+	}
+	return location.String()
+}
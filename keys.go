@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flowkit/v2"
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/config"
+	jsFlow "github.com/onflowser/flow-cli-wasm/js"
+	"github.com/onflowser/flow-cli-wasm/keys"
+)
+
+type GenerateKeyRequest struct {
+	// Defaults to ECDSA_P256.
+	SigAlgo string `json:"sigAlgo"`
+	// Defaults to SHA3_256.
+	HashAlgo string `json:"hashAlgo"`
+	// Optional seed, a random one is used if not provided.
+	Seed string `json:"seed"`
+	// Only used for mnemonic keys, defaults to "m/44'/539'/0'/0/0".
+	DerivationPath string `json:"derivationPath"`
+	// Only used when deriving a key from an existing mnemonic.
+	Mnemonic string `json:"mnemonic"`
+}
+
+type SaveAccountRequest struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	// Hex encoded private key, which is saved to "<name>.pkey" file.
+	PrivateKey string `json:"privateKey"`
+	// Only 0 is supported, since flowkit doesn't save the index of file keys to flow.json.
+	KeyIndex int `json:"keyIndex"`
+	// Defaults to ECDSA_P256.
+	SigAlgo string `json:"sigAlgo"`
+	// Defaults to SHA3_256.
+	HashAlgo string `json:"hashAlgo"`
+}
+
+// generateKey resolves with a new key pair.
+func (w *FlowWasm) generateKey(this js.Value, args []js.Value) any {
+	rawRequest := args[0].String()
+
+	executor := func() (js.Value, error) {
+		request, sigAlgo, hashAlgo, err := parseGenerateKeyRequest(rawRequest)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		privateKey, err := w.kit.GenerateKey(context.Background(), sigAlgo, request.Seed)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return js.ValueOf(serializeKeyPair(privateKey, hashAlgo)), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// generateMnemonicKey resolves with a new mnemonic and the key pair derived from it (BIP-44).
+func (w *FlowWasm) generateMnemonicKey(this js.Value, args []js.Value) any {
+	rawRequest := args[0].String()
+
+	executor := func() (js.Value, error) {
+		request, sigAlgo, hashAlgo, err := parseGenerateKeyRequest(rawRequest)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		privateKey, mnemonic, err := w.kit.GenerateMnemonicKey(context.Background(), sigAlgo, request.DerivationPath)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		keyPair := serializeKeyPair(privateKey, hashAlgo)
+		keyPair["mnemonic"] = mnemonic
+
+		return js.ValueOf(keyPair), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// deriveMnemonicKey resolves with the key pair derived from the given mnemonic (BIP-44).
+func (w *FlowWasm) deriveMnemonicKey(this js.Value, args []js.Value) any {
+	rawRequest := args[0].String()
+
+	executor := func() (js.Value, error) {
+		request, sigAlgo, hashAlgo, err := parseGenerateKeyRequest(rawRequest)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		privateKey, err := w.kit.DerivePrivateKeyFromMnemonic(
+			context.Background(),
+			request.Mnemonic,
+			sigAlgo,
+			request.DerivationPath,
+		)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return js.ValueOf(serializeKeyPair(privateKey, hashAlgo)), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// saveAccount adds an existing account (e.g. created on testnet) to flow.json.
+func (w *FlowWasm) saveAccount(this js.Value, args []js.Value) any {
+	rawRequest := args[0].String()
+
+	executor := func() (js.Value, error) {
+		var request SaveAccountRequest
+		err := json.Unmarshal([]byte(rawRequest), &request)
+		if err != nil {
+			return js.Null(), fmt.Errorf("invalid request: %w", err)
+		}
+
+		if request.Name == "" {
+			return js.Null(), fmt.Errorf("account name is required")
+		}
+
+		if request.KeyIndex != 0 {
+			return js.Null(), fmt.Errorf("key index %d isn't supported, only the first key (index 0) can be saved", request.KeyIndex)
+		}
+
+		sigAlgo, hashAlgo, err := keys.ParseAlgorithms(request.SigAlgo, request.HashAlgo)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		privateKey, err := crypto.DecodePrivateKeyHex(sigAlgo, strings.TrimPrefix(request.PrivateKey, "0x"))
+		if err != nil {
+			return js.Null(), fmt.Errorf("invalid private key: %w", err)
+		}
+
+		return js.Null(), w.saveAccountKey(
+			request.Name,
+			sdk.HexToAddress(request.Address),
+			privateKey,
+			hashAlgo,
+		)
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+func parseGenerateKeyRequest(rawRequest string) (GenerateKeyRequest, crypto.SignatureAlgorithm, crypto.HashAlgorithm, error) {
+	var request GenerateKeyRequest
+	err := json.Unmarshal([]byte(rawRequest), &request)
+	if err != nil {
+		return request, 0, 0, fmt.Errorf("invalid request: %w", err)
+	}
+
+	sigAlgo, hashAlgo, err := keys.ParseAlgorithms(request.SigAlgo, request.HashAlgo)
+
+	return request, sigAlgo, hashAlgo, err
+}
+
+func serializeKeyPair(privateKey crypto.PrivateKey, hashAlgo crypto.HashAlgorithm) map[string]any {
+	return map[string]any{
+		"privateKey": strings.TrimPrefix(privateKey.String(), "0x"),
+		"publicKey":  strings.TrimPrefix(privateKey.PublicKey().String(), "0x"),
+		"sigAlgo":    privateKey.Algorithm().String(),
+		"hashAlgo":   hashAlgo.String(),
+	}
+}
+
+// saveAccountKey writes the private key to a separate "<name>.pkey" file (ignored by git)
+// and adds the account to flow.json with a "file" key that references it.
+// The key index is always 0, since flowkit doesn't save the index of file keys.
+func (w *FlowWasm) saveAccountKey(
+	name string,
+	address sdk.Address,
+	privateKey crypto.PrivateKey,
+	hashAlgo crypto.HashAlgorithm,
+) error {
+	location := accounts.PrivateKeyFile(name, "")
+
+	err := w.config.FileSystem.WriteFile(location, []byte(privateKey.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write private key file: %w", err)
+	}
+
+	err = w.ignoreFile(location)
+	if err != nil {
+		return err
+	}
+
+	w.state.Accounts().AddOrUpdate(&accounts.Account{
+		Name:    name,
+		Address: address,
+		Key:     accounts.NewFileKey(location, 0, privateKey.Algorithm(), hashAlgo, w.config.FileSystem),
+	})
+
+	return w.state.SaveDefault()
+}
+
+// ignoreFile adds the file to .gitignore, so that private keys aren't committed by accident.
+func (w *FlowWasm) ignoreFile(location string) error {
+	const gitignore = ".gitignore"
+
+	// Missing file is treated the same as an empty one.
+	content, _ := w.config.FileSystem.ReadFile(gitignore)
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == location {
+			return nil
+		}
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, location+"\n"...)
+
+	err := w.config.FileSystem.WriteFile(gitignore, content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", gitignore, err)
+	}
+
+	return nil
+}
+
+// bindFileKeys makes file keys from flow.json read private keys through the project file system.
+// Keys loaded from the config would otherwise use the os package, which isn't available in the browser.
+func bindFileKeys(state *flowkit.State, fs flowkit.ReaderWriter) {
+	for _, account := range *state.Accounts() {
+		keyConfig := account.Key.ToConfig()
+		if keyConfig.Type != config.KeyTypeFile {
+			continue
+		}
+
+		account.Key = accounts.NewFileKey(
+			keyConfig.Location,
+			// Not part of the config of file keys.
+			account.Key.Index(),
+			keyConfig.SigAlgo,
+			keyConfig.HashAlgo,
+			fs,
+		)
+		state.Accounts().AddOrUpdate(&account)
+	}
+}
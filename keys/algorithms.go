@@ -0,0 +1,30 @@
+// Package keys contains helpers for account keys that don't depend on the JS runtime, so that they can be tested natively.
+package keys
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// ParseAlgorithms only accepts algorithms that are supported for Flow account keys.
+// Empty values default to ECDSA_P256 and SHA3_256.
+func ParseAlgorithms(rawSigAlgo string, rawHashAlgo string) (crypto.SignatureAlgorithm, crypto.HashAlgorithm, error) {
+	sigAlgo := crypto.ECDSA_P256
+	if rawSigAlgo != "" {
+		sigAlgo = crypto.StringToSignatureAlgorithm(rawSigAlgo)
+	}
+	if sigAlgo != crypto.ECDSA_P256 && sigAlgo != crypto.ECDSA_secp256k1 {
+		return 0, 0, fmt.Errorf("unsupported signature algorithm: %s", rawSigAlgo)
+	}
+
+	hashAlgo := crypto.SHA3_256
+	if rawHashAlgo != "" {
+		hashAlgo = crypto.StringToHashAlgorithm(rawHashAlgo)
+	}
+	if hashAlgo != crypto.SHA2_256 && hashAlgo != crypto.SHA3_256 {
+		return 0, 0, fmt.Errorf("unsupported hash algorithm: %s", rawHashAlgo)
+	}
+
+	return sigAlgo, hashAlgo, nil
+}
@@ -0,0 +1,79 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+func TestParseAlgorithms(t *testing.T) {
+	tests := []struct {
+		name     string
+		sigAlgo  string
+		hashAlgo string
+		wantSig  crypto.SignatureAlgorithm
+		wantHash crypto.HashAlgorithm
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			wantSig:  crypto.ECDSA_P256,
+			wantHash: crypto.SHA3_256,
+		},
+		{
+			name:     "secp256k1 with SHA2",
+			sigAlgo:  "ECDSA_secp256k1",
+			hashAlgo: "SHA2_256",
+			wantSig:  crypto.ECDSA_secp256k1,
+			wantHash: crypto.SHA2_256,
+		},
+		{
+			name:     "only hash algorithm",
+			hashAlgo: "SHA2_256",
+			wantSig:  crypto.ECDSA_P256,
+			wantHash: crypto.SHA2_256,
+		},
+		{
+			name:    "BLS isn't supported for account keys",
+			sigAlgo: "BLS_BLS12_381",
+			wantErr: true,
+		},
+		{
+			name:     "SHA3_384 isn't supported for account keys",
+			hashAlgo: "SHA3_384",
+			wantErr:  true,
+		},
+		{
+			name:    "unknown signature algorithm",
+			sigAlgo: "RSA",
+			wantErr: true,
+		},
+		{
+			name:     "unknown hash algorithm",
+			hashAlgo: "MD5",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sigAlgo, hashAlgo, err := ParseAlgorithms(test.sigAlgo, test.hashAlgo)
+			if test.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sigAlgo != test.wantSig {
+				t.Errorf("signature algorithm is %s, expected %s", sigAlgo, test.wantSig)
+			}
+			if hashAlgo != test.wantHash {
+				t.Errorf("hash algorithm is %s, expected %s", hashAlgo, test.wantHash)
+			}
+		})
+	}
+}
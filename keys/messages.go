@@ -0,0 +1,25 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	sdk "github.com/onflow/flow-go-sdk"
+)
+
+// DecodeTransactionMessage decodes the hex encoded message (optionally "0x" prefixed)
+// and rejects messages without the transaction domain tag, so that keys can't be used to sign arbitrary data.
+func DecodeTransactionMessage(rawMessage string) ([]byte, error) {
+	message, err := hex.DecodeString(strings.TrimPrefix(rawMessage, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+
+	if !bytes.HasPrefix(message, sdk.TransactionDomainTag[:]) {
+		return nil, fmt.Errorf("invalid message: only transactions with the transaction domain tag can be signed")
+	}
+
+	return message, nil
+}
@@ -0,0 +1,54 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+
+	sdk "github.com/onflow/flow-go-sdk"
+)
+
+func TestDecodeTransactionMessage(t *testing.T) {
+	domainTag := hex.EncodeToString(sdk.TransactionDomainTag[:])
+
+	tests := []struct {
+		name       string
+		rawMessage string
+		wantErr    bool
+	}{
+		{
+			name:       "transaction message",
+			rawMessage: domainTag + "f8a0",
+		},
+		{
+			name:       "0x prefixed transaction message",
+			rawMessage: "0x" + domainTag + "f8a0",
+		},
+		{
+			name:       "missing domain tag",
+			rawMessage: "f8a0",
+			wantErr:    true,
+		},
+		{
+			name:       "user message domain tag",
+			rawMessage: hex.EncodeToString(sdk.UserDomainTag[:]) + "f8a0",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid hex",
+			rawMessage: domainTag + "xyz",
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeTransactionMessage(test.rawMessage)
+			if test.wantErr && err == nil {
+				t.Error("expected an error")
+			}
+			if !test.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -1,20 +1,20 @@
 package main
 
 import (
-	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"github.com/onflow/flow-emulator/storage/memstore"
 	"github.com/onflow/flowkit/v2"
 	"github.com/onflow/flowkit/v2/config"
 	"github.com/onflow/flowkit/v2/deps"
-	"github.com/onflow/flowkit/v2/output"
+	"github.com/onflowser/flow-cli-wasm/blockchain"
 	jsFlow "github.com/onflowser/flow-cli-wasm/js"
 	"github.com/onflowser/flow-cli-wasm/logging"
+	"github.com/onflowser/flow-cli-wasm/store"
 	"syscall/js"
+	"time"
 
 	"github.com/onflow/flow-emulator/emulator"
+	flowgo "github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flowkit/v2/gateway"
 )
 
@@ -23,23 +23,32 @@ type Config struct {
 	LogFormat  string // "text" or "json". Defaults to "json" if "logs" writer is used.
 	FileSystem flowkit.ReaderWriter
 	Prompter   deps.Prompter
+	Emulator   EmulatorConfig
 }
 
 type FlowWasm struct {
 	config    Config
 	state     *flowkit.State
-	gateway   *gateway.EmulatorGateway
+	gateway   *blockchain.Gateway
+	store     *store.Store
+	gateways  map[string]gateway.Gateway
 	logger    *logging.Logger
 	kit       *flowkit.Flowkit
 	installer *deps.DependencyInstaller
 }
 
 func main() {
+	emulatorConfig, err := parseEmulatorConfig(js.Global().Get("emulatorConfig"))
+	if err != nil {
+		panic(err)
+	}
+
 	w := New(Config{
 		Verbose:    true,
 		LogFormat:  "text",
 		Prompter:   jsFlow.NewPrompter(js.Global().Get("prompter")),
 		FileSystem: jsFlow.NewFileSystem(js.Global().Get("flowFileSystem")),
+		Emulator:   emulatorConfig,
 	})
 
 	// Register APIs
@@ -48,6 +57,31 @@ func main() {
 	js.Global().Set("getLogs", js.FuncOf(w.getLogs))
 	js.Global().Set("install", js.FuncOf(w.install))
 	js.Global().Set("deploy", js.FuncOf(w.deploy))
+	js.Global().Set("ping", js.FuncOf(w.ping))
+	js.Global().Set("createSnapshot", js.FuncOf(w.createSnapshot))
+	js.Global().Set("loadSnapshot", js.FuncOf(w.loadSnapshot))
+	js.Global().Set("listSnapshots", js.FuncOf(w.listSnapshots))
+	js.Global().Set("deleteSnapshot", js.FuncOf(w.deleteSnapshot))
+	js.Global().Set("exportState", js.FuncOf(w.exportState))
+	js.Global().Set("importState", js.FuncOf(w.importState))
+	js.Global().Set("setMiningMode", js.FuncOf(w.setMiningMode))
+	js.Global().Set("commitBlock", js.FuncOf(w.commitBlock))
+	js.Global().Set("pendingTransactions", js.FuncOf(w.pendingTransactions))
+	js.Global().Set("setNextBlockTimestamp", js.FuncOf(w.setNextBlockTimestamp))
+	js.Global().Set("advanceTime", js.FuncOf(w.advanceTime))
+	js.Global().Set("mineBlocks", js.FuncOf(w.mineBlocks))
+	js.Global().Set("createAccount", js.FuncOf(w.createAccount))
+	js.Global().Set("addKey", js.FuncOf(w.addKey))
+	js.Global().Set("revokeKey", js.FuncOf(w.revokeKey))
+	js.Global().Set("listAccounts", js.FuncOf(w.listAccounts))
+	js.Global().Set("generateKey", js.FuncOf(w.generateKey))
+	js.Global().Set("generateMnemonicKey", js.FuncOf(w.generateMnemonicKey))
+	js.Global().Set("deriveMnemonicKey", js.FuncOf(w.deriveMnemonicKey))
+	js.Global().Set("saveAccount", js.FuncOf(w.saveAccount))
+	js.Global().Set("signingAccount", js.FuncOf(w.signingAccount))
+	js.Global().Set("signTransaction", js.FuncOf(w.signTransaction))
+	js.Global().Set("runScript", js.FuncOf(w.runScript))
+	js.Global().Set("sendTransaction", js.FuncOf(w.sendTransaction))
 
 	// Indicate the emulator started and APIs were initialized
 	js.Global().Call("onStarted")
@@ -61,22 +95,40 @@ func New(config Config) *FlowWasm {
 		Verbose:   config.Verbose,
 		LogFormat: config.LogFormat,
 	})
-	store := memstore.New()
+	emulatorStore, err := newStore(config)
+	if err != nil {
+		panic(err)
+	}
 
-	emulatorGateway := gateway.NewEmulatorGatewayWithOpts(
-		&gateway.EmulatorKey{
-			PublicKey: emulator.DefaultServiceKey().AccountKey().PublicKey,
-			SigAlgo:   emulator.DefaultServiceKeySigAlgo,
-			HashAlgo:  emulator.DefaultServiceKeyHashAlgo,
-		},
-		gateway.WithEmulatorOptions(
-			emulator.WithLogger(*logger.Zerolog()),
-			emulator.WithStore(store),
-			emulator.WithTransactionValidationEnabled(false),
-			emulator.WithStorageLimitEnabled(false),
-			emulator.WithTransactionFeesEnabled(false),
-		),
-	)
+	externalGateways := jsExternalGateways()
+
+	if config.Emulator.Fork != "" {
+		err = forkNetwork(emulatorStore, externalGateways, config.Emulator)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	emulatorOptions, err := config.Emulator.options()
+	if err != nil {
+		panic(err)
+	}
+
+	b, err := emulator.New(append(
+		emulatorOptions,
+		emulator.WithLogger(*logger.Zerolog()),
+		emulator.WithStore(emulatorStore),
+	)...)
+	if err != nil {
+		panic(err)
+	}
+
+	emulatorGateway := blockchain.NewGateway(b, logger.Zerolog())
+
+	err = emulatorGateway.AdvanceToBlockHeight(config.Emulator.InitialBlockHeight)
+	if err != nil {
+		panic(err)
+	}
 
 	configFilePaths := []string{
 		"flow.json",
@@ -85,18 +137,20 @@ func New(config Config) *FlowWasm {
 	if err != nil {
 		panic(err)
 	}
+	bindFileKeys(state, config.FileSystem)
 
-	network, err := state.Networks().ByName("emulator")
+	network, err := state.Networks().ByName(config.Emulator.networkName())
 	if err != nil {
 		panic(err)
 	}
 
 	kit := flowkit.NewFlowkit(state, *network, emulatorGateway, logger)
+	gateways := jsGateways(emulatorGateway, externalGateways)
 
 	installer, err := deps.NewDependencyInstaller(
 		state,
 		config.Prompter,
-		deps.WithGateways(jsGateways(emulatorGateway)),
+		deps.WithGateways(gateways),
 		deps.WithLogger(logger),
 		deps.WithSaveState(),
 	)
@@ -107,24 +161,45 @@ func New(config Config) *FlowWasm {
 
 	return &FlowWasm{
 		config:    config,
+		state:     state,
 		gateway:   emulatorGateway,
+		store:     emulatorStore,
+		gateways:  gateways,
 		logger:    logger,
 		kit:       kit,
 		installer: installer,
 	}
 }
 
-func jsGateways(emulatorGateway gateway.Gateway) map[string]gateway.Gateway {
-	testnetGateway := jsFlow.NewExternalGateway(js.Global().Get("testnetGateway"))
-	mainnetGateway := jsFlow.NewExternalGateway(js.Global().Get("mainnetGateway"))
-	previewnetGateway := jsFlow.NewExternalGateway(js.Global().Get("previewnetGateway"))
+// Directory (relative to the project root) where the emulator state is persisted.
+const stateDir = ".flow-wasm/state"
+
+func newStore(config Config) (*store.Store, error) {
+	if !config.Emulator.PersistState {
+		return store.New(), nil
+	}
+
+	return store.NewPersistent(config.FileSystem, stateDir)
+}
+
+func jsExternalGateways() map[string]*jsFlow.ExternalGateway {
+	return map[string]*jsFlow.ExternalGateway{
+		config.TestnetNetwork.Name:    jsFlow.NewExternalGateway(js.Global().Get("testnetGateway")),
+		config.MainnetNetwork.Name:    jsFlow.NewExternalGateway(js.Global().Get("mainnetGateway")),
+		config.PreviewnetNetwork.Name: jsFlow.NewExternalGateway(js.Global().Get("previewnetGateway")),
+	}
+}
+
+func jsGateways(emulatorGateway gateway.Gateway, externalGateways map[string]*jsFlow.ExternalGateway) map[string]gateway.Gateway {
+	gateways := map[string]gateway.Gateway{
+		config.EmulatorNetwork.Name: emulatorGateway,
+	}
 
-	return map[string]gateway.Gateway{
-		config.EmulatorNetwork.Name:   emulatorGateway,
-		config.TestnetNetwork.Name:    testnetGateway,
-		config.MainnetNetwork.Name:    mainnetGateway,
-		config.PreviewnetNetwork.Name: previewnetGateway,
+	for name, externalGateway := range externalGateways {
+		gateways[name] = externalGateway
 	}
+
+	return gateways
 }
 
 func (w *FlowWasm) install(this js.Value, args []js.Value) any {
@@ -136,42 +211,248 @@ func (w *FlowWasm) install(this js.Value, args []js.Value) any {
 	return jsFlow.AsyncWork(executor)
 }
 
-func (w *FlowWasm) getLogs(this js.Value, args []js.Value) interface{} {
-	res, err := json.Marshal(w.logger.LogsHistory())
+// ping checks if the gateway of the network (e.g. "testnet") is reachable.
+func (w *FlowWasm) ping(this js.Value, args []js.Value) any {
+	networkName := args[0].String()
 
-	if err != nil {
-		panic(err)
+	executor := func() (js.Value, error) {
+		gtw, ok := w.gateways[networkName]
+		if !ok {
+			return js.Null(), fmt.Errorf("gateway for network %s not found", networkName)
+		}
+
+		return js.Null(), gtw.Ping()
 	}
 
-	return string(res)
+	return jsFlow.AsyncWork(executor)
+}
+
+func (w *FlowWasm) createSnapshot(this js.Value, args []js.Value) any {
+	name := args[0].String()
+
+	executor := func() (js.Value, error) {
+		return js.Null(), w.gateway.CreateSnapshot(name)
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// loadSnapshot restores the state captured with createSnapshot.
+// Transactions of the pending block are discarded.
+func (w *FlowWasm) loadSnapshot(this js.Value, args []js.Value) any {
+	name := args[0].String()
+
+	executor := func() (js.Value, error) {
+		return js.Null(), w.gateway.LoadSnapshot(name)
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+func (w *FlowWasm) listSnapshots(this js.Value, args []js.Value) any {
+	executor := func() (js.Value, error) {
+		names, err := w.gateway.Snapshots()
+		if err != nil {
+			return js.Null(), err
+		}
+
+		values := make([]any, 0, len(names))
+		for _, name := range names {
+			values = append(values, name)
+		}
+
+		return js.ValueOf(values), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+func (w *FlowWasm) deleteSnapshot(this js.Value, args []js.Value) any {
+	name := args[0].String()
+
+	executor := func() (js.Value, error) {
+		return js.Null(), w.gateway.DeleteSnapshot(func() error {
+			return w.store.DeleteSnapshot(name)
+		})
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// setMiningMode accepts "auto", "manual" or "interval" mode.
+// The second argument is the block interval in milliseconds and is only used with "interval" mode.
+func (w *FlowWasm) setMiningMode(this js.Value, args []js.Value) any {
+	mode := blockchain.MiningMode(args[0].String())
+	var interval time.Duration
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		interval = time.Duration(args[1].Int()) * time.Millisecond
+	}
+
+	executor := func() (js.Value, error) {
+		return js.Null(), w.gateway.SetMiningMode(mode, interval)
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// commitBlock executes pending transactions and resolves with the committed block ID and height.
+func (w *FlowWasm) commitBlock(this js.Value, args []js.Value) any {
+	executor := func() (js.Value, error) {
+		block, err := w.gateway.CommitBlock()
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return committedBlockValue(block), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+func committedBlockValue(block *flowgo.Block) js.Value {
+	return js.ValueOf(map[string]any{
+		"id":     block.ID().String(),
+		"height": block.Header.Height,
+	})
+}
+
+// pendingTransactions resolves with IDs of transactions that weren't committed yet.
+func (w *FlowWasm) pendingTransactions(this js.Value, args []js.Value) any {
+	executor := func() (js.Value, error) {
+		ids := w.gateway.PendingTransactions()
+
+		values := make([]any, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, id.String())
+		}
+
+		return js.ValueOf(values), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// setNextBlockTimestamp accepts a unix timestamp in milliseconds.
+func (w *FlowWasm) setNextBlockTimestamp(this js.Value, args []js.Value) any {
+	timestamp := time.UnixMilli(int64(args[0].Float()))
+
+	executor := func() (js.Value, error) {
+		return js.Null(), w.gateway.SetNextBlockTimestamp(timestamp)
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// advanceTime accepts a duration in milliseconds.
+func (w *FlowWasm) advanceTime(this js.Value, args []js.Value) any {
+	duration := time.Duration(args[0].Float() * float64(time.Millisecond))
+
+	executor := func() (js.Value, error) {
+		return js.Null(), w.gateway.AdvanceTime(duration)
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// mineBlocks resolves with the ID and height of the last committed block.
+func (w *FlowWasm) mineBlocks(this js.Value, args []js.Value) any {
+	count := args[0].Int()
+
+	executor := func() (js.Value, error) {
+		if count < 1 {
+			return js.Null(), fmt.Errorf("block count must be positive")
+		}
+
+		block, err := w.gateway.MineBlocks(uint(count))
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return committedBlockValue(block), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
 }
 
-func (w *FlowWasm) deploy(this js.Value, args []js.Value) interface{} {
+// exportState resolves with a binary archive (Uint8Array) of the current emulator state.
+func (w *FlowWasm) exportState(this js.Value, args []js.Value) any {
 	executor := func() (js.Value, error) {
-		contracts, err := w.kit.DeployProject(context.Background(), flowkit.UpdateExistingContract(true))
+		var archive []byte
+		err := w.gateway.ReadState(func() error {
+			var err error
+			archive, err = w.store.Export(w.archiveMetadata())
+			return err
+		})
 		if err != nil {
-			var projectErr *flowkit.ProjectDeploymentError
-			if errors.As(err, &projectErr) {
-				for name, err := range projectErr.Contracts() {
-					w.logger.Info(fmt.Sprintf(
-						"%s Failed to deploy contract %s: %s",
-						output.ErrorEmoji(),
-						name,
-						err.Error(),
-					))
-				}
-				return js.Null(), fmt.Errorf("failed deploying all contracts")
-			}
+			return js.Null(), err
+		}
+
+		value := js.Global().Get("Uint8Array").New(len(archive))
+		js.CopyBytesToJS(value, archive)
+
+		return value, nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// importState replaces the emulator state with the archive (Uint8Array) created by exportState.
+func (w *FlowWasm) importState(this js.Value, args []js.Value) any {
+	encodedArchive := make([]byte, args[0].Get("length").Int())
+	js.CopyBytesToGo(encodedArchive, args[0])
 
+	executor := func() (js.Value, error) {
+		archive, err := store.DecodeArchive(encodedArchive)
+		if err != nil {
 			return js.Null(), err
 		}
 
-		for _, contract := range contracts {
-			w.logger.Info(fmt.Sprintf("deployed %s contract", contract.Name))
+		current := w.archiveMetadata()
+		if archive.Metadata.ChainID != current.ChainID {
+			return js.Null(), fmt.Errorf(
+				"archive was exported from chain %s, but the emulator is running %s",
+				archive.Metadata.ChainID,
+				current.ChainID,
+			)
 		}
 
+		if archive.Metadata.ServiceKey.PublicKey != current.ServiceKey.PublicKey {
+			return js.Null(), fmt.Errorf(
+				"archive was exported with a different service key, configure the emulator with service key %s",
+				archive.Metadata.ServiceKey.PublicKey,
+			)
+		}
+
+		err = w.gateway.ReplaceState(func() error {
+			return w.store.Import(archive)
+		})
+
 		return js.Null(), err
 	}
 
 	return jsFlow.AsyncWork(executor)
 }
+
+func (w *FlowWasm) archiveMetadata() store.ArchiveMetadata {
+	b := w.gateway.Blockchain()
+	serviceKey := b.ServiceKey()
+
+	return store.ArchiveMetadata{
+		ChainID: b.GetChain().ChainID().String(),
+		ServiceKey: store.ArchiveServiceKey{
+			PublicKey: serviceKey.PublicKey.String(),
+			SigAlgo:   serviceKey.SigAlgo.String(),
+			HashAlgo:  serviceKey.HashAlgo.String(),
+		},
+	}
+}
+
+func (w *FlowWasm) getLogs(this js.Value, args []js.Value) interface{} {
+	res, err := json.Marshal(w.logger.LogsHistory())
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(res)
+}
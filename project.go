@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flowkit/v2"
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/arguments"
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/transactions"
+	jsFlow "github.com/onflowser/flow-cli-wasm/js"
+)
+
+// runScript executes the script file with JSON-Cadence encoded arguments (e.g. `[{"type":"String","value":"Hi"}]`).
+// Imports are resolved with contracts and aliases of the network from flow.json.
+// Resolves with the JSON-Cadence encoded result.
+func (w *FlowWasm) runScript(this js.Value, args []js.Value) any {
+	path := args[0].String()
+	argsJSON := args[1].String()
+	networkName := args[2].String()
+
+	executor := func() (js.Value, error) {
+		kit, err := w.kitForNetwork(networkName)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		script, err := w.readScript(path, argsJSON)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		value, err := kit.ExecuteScript(context.Background(), script, flowkit.LatestScriptQuery)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		encodedValue, err := jsoncdc.Encode(value)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return js.ValueOf(string(encodedValue)), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// sendTransaction sends the transaction file with JSON-Cadence encoded arguments
+// and resolves with the result once the transaction is sealed.
+// Signers are names of flow.json accounts, where the first one is also the proposer and payer.
+// The emulator service account signs the transaction if no signers are provided,
+// which is only allowed on the emulator network.
+func (w *FlowWasm) sendTransaction(this js.Value, args []js.Value) any {
+	path := args[0].String()
+	argsJSON := args[1].String()
+	signersJSON := args[2].String()
+	networkName := args[3].String()
+
+	executor := func() (js.Value, error) {
+		var signerNames []string
+		err := json.Unmarshal([]byte(signersJSON), &signerNames)
+		if err != nil {
+			return js.Null(), fmt.Errorf("invalid signers: %w", err)
+		}
+
+		kit, err := w.kitForNetwork(networkName)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		script, err := w.readScript(path, argsJSON)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		roles, err := w.transactionRoles(signerNames, networkName)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		_, result, err := kit.SendTransaction(context.Background(), roles, script, sdk.DefaultTransactionGasLimit)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		serializedResult, err := serializeProjectTransactionResult(result)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return js.ValueOf(serializedResult), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// kitForNetwork returns flowkit that uses the gateway of the network (e.g. "testnet") and resolves imports for it.
+func (w *FlowWasm) kitForNetwork(networkName string) (*flowkit.Flowkit, error) {
+	if networkName == "" || networkName == config.EmulatorNetwork.Name {
+		return w.kit, nil
+	}
+
+	gtw, ok := w.gateways[networkName]
+	if !ok {
+		return nil, fmt.Errorf("gateway for network %s not found", networkName)
+	}
+
+	network, err := w.state.Networks().ByName(networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	return flowkit.NewFlowkit(w.state, *network, gtw, w.logger), nil
+}
+
+// readScript reads the Cadence file from the project file system.
+// The path is kept as the script location, so that relative imports can be resolved.
+func (w *FlowWasm) readScript(path string, argsJSON string) (flowkit.Script, error) {
+	code, err := w.config.FileSystem.ReadFile(path)
+	if err != nil {
+		return flowkit.Script{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var args []cadence.Value
+	if argsJSON != "" {
+		args, err = arguments.ParseJSON(argsJSON)
+		if err != nil {
+			return flowkit.Script{}, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	return flowkit.Script{
+		Code:     code,
+		Args:     args,
+		Location: path,
+	}, nil
+}
+
+func (w *FlowWasm) transactionRoles(signerNames []string, networkName string) (transactions.AccountRoles, error) {
+	if len(signerNames) == 0 {
+		// The emulator service account must never be used to sign transactions for other networks.
+		if networkName != "" && networkName != config.EmulatorNetwork.Name {
+			return transactions.AccountRoles{}, fmt.Errorf("signers are required on network %s", networkName)
+		}
+		signerNames = []string{""}
+	}
+
+	signers := make([]accounts.Account, 0, len(signerNames))
+	for _, name := range signerNames {
+		account, err := w.accountByName(name)
+		if err != nil {
+			return transactions.AccountRoles{}, err
+		}
+		signers = append(signers, *account)
+	}
+
+	return transactions.AccountRoles{
+		Proposer:    signers[0],
+		Payer:       signers[0],
+		Authorizers: signers,
+	}, nil
+}
+
+func serializeProjectTransactionResult(result *sdk.TransactionResult) (map[string]any, error) {
+	events := make([]any, 0, len(result.Events))
+	for _, event := range result.Events {
+		payload, err := jsoncdc.Encode(event.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event payload: %w", err)
+		}
+
+		events = append(events, map[string]any{
+			"type":       event.Type,
+			"eventIndex": event.EventIndex,
+			// JSON-Cadence encoded event value
+			"payload": string(payload),
+		})
+	}
+
+	errorMessage := ""
+	if result.Error != nil {
+		errorMessage = result.Error.Error()
+	}
+
+	return map[string]any{
+		"id":           result.TransactionID.Hex(),
+		"blockId":      result.BlockID.Hex(),
+		"blockHeight":  result.BlockHeight,
+		"status":       int(result.Status),
+		"statusString": result.Status.String(),
+		"errorMessage": errorMessage,
+		"events":       events,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"syscall/js"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	jsFlow "github.com/onflowser/flow-cli-wasm/js"
+	"github.com/onflowser/flow-cli-wasm/keys"
+)
+
+// signingAccount resolves with the address and key index that the flow.json account signs with.
+// The emulator service account is used if the account name is empty.
+func (w *FlowWasm) signingAccount(this js.Value, args []js.Value) any {
+	accountName := args[0].String()
+
+	executor := func() (js.Value, error) {
+		account, err := w.accountByName(accountName)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		return js.ValueOf(map[string]any{
+			"addr":  account.Address.HexWithPrefix(),
+			"keyId": account.Key.Index(),
+		}), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+// signTransaction signs the hex encoded message with the key of the flow.json account
+// and resolves with a composite signature (as expected by FCL signing functions).
+// The message must already include the transaction domain tag, which FCL adds when encoding transactions.
+// Other messages are rejected, so that the keys can't be used to sign arbitrary data.
+func (w *FlowWasm) signTransaction(this js.Value, args []js.Value) any {
+	rawMessage := args[0].String()
+	accountName := args[1].String()
+
+	executor := func() (js.Value, error) {
+		message, err := keys.DecodeTransactionMessage(rawMessage)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		account, err := w.accountByName(accountName)
+		if err != nil {
+			return js.Null(), err
+		}
+
+		signer, err := account.Key.Signer(context.Background())
+		if err != nil {
+			return js.Null(), fmt.Errorf("failed to load key of account %s: %w", account.Name, err)
+		}
+
+		signature, err := signer.Sign(message)
+		if err != nil {
+			return js.Null(), fmt.Errorf("failed to sign with account %s: %w", account.Name, err)
+		}
+
+		return js.ValueOf(map[string]any{
+			"addr":      account.Address.HexWithPrefix(),
+			"keyId":     account.Key.Index(),
+			"signature": hex.EncodeToString(signature),
+		}), nil
+	}
+
+	return jsFlow.AsyncWork(executor)
+}
+
+func (w *FlowWasm) accountByName(name string) (*accounts.Account, error) {
+	if name == "" {
+		return w.state.EmulatorServiceAccount()
+	}
+
+	return w.state.Accounts().ByName(name)
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	archiveMagic         = "FLOWWASM"
+	archiveFormatVersion = 1
+)
+
+// Archive is a portable copy of the full emulator state (ledger, blocks, transactions, events,...).
+//
+// Binary format:
+//   - magic bytes ("FLOWWASM")
+//   - format version (uvarint)
+//   - JSON encoded metadata (uvarint length prefixed)
+//   - entries as encoded by encodeEntries
+type Archive struct {
+	Metadata ArchiveMetadata
+	entries  []entry
+}
+
+type ArchiveMetadata struct {
+	ChainID    string            `json:"chainId"`
+	ServiceKey ArchiveServiceKey `json:"serviceKey"`
+}
+
+// ArchiveServiceKey is included in the archive, so that the state can be restored by an emulator with the same service key.
+// The private key is never included, since archives are meant to be shared.
+type ArchiveServiceKey struct {
+	PublicKey string `json:"publicKey"`
+	SigAlgo   string `json:"sigAlgo"`
+	HashAlgo  string `json:"hashAlgo"`
+}
+
+// Export captures the current state into an encoded archive.
+func (s *Store) Export(metadata ArchiveMetadata) ([]byte, error) {
+	s.mu.RLock()
+	entries := s.data.entries()
+	s.mu.RUnlock()
+
+	encodedMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := []byte(archiveMagic)
+	buf = binary.AppendUvarint(buf, archiveFormatVersion)
+	buf = appendBytes(buf, encodedMetadata)
+	buf = append(buf, encodeEntries(entries)...)
+
+	return buf, nil
+}
+
+func DecodeArchive(encoded []byte) (*Archive, error) {
+	if !bytes.HasPrefix(encoded, []byte(archiveMagic)) {
+		return nil, fmt.Errorf("invalid archive: missing header")
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(encoded[len(archiveMagic):]))
+
+	version, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	if version != archiveFormatVersion {
+		return nil, fmt.Errorf("unsupported archive format version: %d", version)
+	}
+
+	encodedMetadata, err := readBytes(reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive metadata: %w", err)
+	}
+
+	var archive Archive
+	err = json.Unmarshal(encodedMetadata, &archive.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive metadata: %w", err)
+	}
+
+	encodedEntries, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	archive.entries, err = decodeEntries(encodedEntries)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive entries: %w", err)
+	}
+
+	return &archive, nil
+}
+
+// Import replaces the current state with the archived state.
+// The blockchain must be reloaded afterward, see blockchain.Gateway.ReplaceState.
+func (s *Store) Import(archive *Archive) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := newData()
+	for _, e := range archive.entries {
+		d.apply(e)
+	}
+	s.data = d
+
+	if s.persistence != nil {
+		return s.persistence.reset(s.data)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	metadata := ArchiveMetadata{
+		ChainID: "flow-emulator",
+		ServiceKey: ArchiveServiceKey{
+			PublicKey: "0x01",
+			SigAlgo:   "ECDSA_P256",
+			HashAlgo:  "SHA3_256",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		persistent bool
+	}{
+		{
+			name:       "in-memory store",
+			persistent: false,
+		},
+		{
+			name:       "persistent store",
+			persistent: true,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := New()
+			err := source.SetBytes(ctx, "values", []byte("key"), []byte("value"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = source.SetBytesWithVersion(ctx, "ledger", []byte("register"), []byte("first"), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = source.SetBytesWithVersion(ctx, "ledger", []byte("register"), []byte("second"), 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded, err := source.Export(metadata)
+			if err != nil {
+				t.Fatalf("failed to export: %v", err)
+			}
+
+			archive, err := DecodeArchive(encoded)
+			if err != nil {
+				t.Fatalf("failed to decode archive: %v", err)
+			}
+			if archive.Metadata != metadata {
+				t.Errorf("decoded metadata %v, expected %v", archive.Metadata, metadata)
+			}
+
+			fileSystem := newMemoryFileSystem()
+			target := New()
+			if test.persistent {
+				target, err = NewPersistent(fileSystem, "state")
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Existing values must not survive the import.
+			err = target.SetBytes(ctx, "values", []byte("stale"), []byte("value"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = target.Import(archive)
+			if err != nil {
+				t.Fatalf("failed to import: %v", err)
+			}
+			if !reflect.DeepEqual(target.data, source.data) {
+				t.Errorf("imported data doesn't match the exported data")
+			}
+
+			if test.persistent {
+				restored, err := NewPersistent(fileSystem, "state")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(restored.data, source.data) {
+					t.Errorf("restored data doesn't match the imported data")
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidArchive(t *testing.T) {
+	valid, err := New().Export(ArchiveMetadata{ChainID: "flow-emulator"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsupportedVersion := binary.AppendUvarint([]byte(archiveMagic), archiveFormatVersion+1)
+
+	tests := []struct {
+		name    string
+		encoded []byte
+	}{
+		{
+			name:    "empty",
+			encoded: []byte{},
+		},
+		{
+			name:    "missing header",
+			encoded: valid[1:],
+		},
+		{
+			name:    "unsupported format version",
+			encoded: append(unsupportedVersion, valid[len(archiveMagic)+1:]...),
+		},
+		{
+			name:    "truncated metadata",
+			encoded: valid[:len(archiveMagic)+3],
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeArchive(test.encoded)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// entry is a single write to the key-value store.
+type entry struct {
+	store     string
+	key       []byte
+	value     []byte
+	version   uint64
+	versioned bool
+}
+
+func encodeEntries(entries []entry) []byte {
+	var buf []byte
+
+	for _, e := range entries {
+		var flags byte
+		if e.versioned {
+			flags = 1
+		}
+
+		buf = append(buf, flags)
+		buf = appendBytes(buf, []byte(e.store))
+		buf = appendBytes(buf, e.key)
+		buf = binary.AppendUvarint(buf, e.version)
+		buf = appendBytes(buf, e.value)
+	}
+
+	return buf
+}
+
+func appendBytes(buf []byte, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func decodeEntries(encoded []byte) ([]entry, error) {
+	entries := make([]entry, 0)
+	reader := bufio.NewReader(bytes.NewReader(encoded))
+
+	for {
+		flags, err := reader.ReadByte()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		e := entry{versioned: flags&1 == 1}
+
+		store, err := readBytes(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode entry store: %w", err)
+		}
+		e.store = string(store)
+
+		e.key, err = readBytes(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode entry key: %w", err)
+		}
+
+		e.version, err = binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode entry version: %w", err)
+		}
+
+		e.value, err = readBytes(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode entry value: %w", err)
+		}
+
+		entries = append(entries, e)
+	}
+}
+
+func readBytes(reader *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	value := make([]byte, length)
+	_, err = io.ReadFull(reader, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
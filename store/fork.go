@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// Remote provides the state of the forked network.
+type Remote interface {
+	// GetRegisterValue returns an empty value if the register doesn't exist.
+	GetRegisterValue(ctx context.Context, height uint64, id flowgo.RegisterID) ([]byte, error)
+}
+
+type fork struct {
+	remote Remote
+	height uint64
+}
+
+// Fork makes the store fallback to the remote network for registers that aren't stored locally.
+// Remote registers are always read at the fork height, so that the state is consistent, and cached afterward.
+//
+// The block is stored as the latest block, unless the store was already forked (e.g. restored from persisted state).
+func (s *Store) Fork(ctx context.Context, remote Remote, block *flowgo.Block) error {
+	forkHeight, err := s.ForkedBlockHeight(ctx)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		if _, err := s.LatestBlockHeight(ctx); err == nil {
+			return fmt.Errorf("can't fork the network, because the emulator state already exists")
+		}
+
+		forkHeight = block.Header.Height
+
+		err = s.StoreForkedBlockHeight(ctx, forkHeight)
+		if err != nil {
+			return err
+		}
+
+		err = s.StoreBlock(ctx, block)
+		if err != nil {
+			return err
+		}
+
+		err = s.flush()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.fork = &fork{
+		remote: remote,
+		height: forkHeight,
+	}
+
+	return nil
+}
+
+func (s *Store) LedgerByHeight(ctx context.Context, blockHeight uint64) (snapshot.StorageSnapshot, error) {
+	ledger, err := s.DefaultStore.LedgerByHeight(ctx, blockHeight)
+	if err != nil || s.fork == nil {
+		return ledger, err
+	}
+
+	return snapshot.NewReadFuncStorageSnapshot(func(id flowgo.RegisterID) (flowgo.RegisterValue, error) {
+		value, err := ledger.Get(id)
+		// Value is nil only if the register wasn't stored locally.
+		if err != nil || value != nil {
+			return value, err
+		}
+
+		remoteHeight := min(blockHeight, s.fork.height)
+		value, err = s.fork.remote.GetRegisterValue(ctx, remoteHeight, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch register %s from the forked network: %w", id, err)
+		}
+
+		err = s.SetBytesWithVersion(
+			ctx,
+			s.KeyGenerator.Storage(storage.LedgerStoreName),
+			[]byte(id.String()),
+			value,
+			remoteHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	}), nil
+}
@@ -0,0 +1,214 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-emulator/storage"
+	flowgo "github.com/onflow/flow-go/model/flow"
+)
+
+// memoryRemote serves register values from memory and records the heights of all requests.
+type memoryRemote struct {
+	registers map[flowgo.RegisterID][]byte
+	heights   []uint64
+}
+
+func (r *memoryRemote) GetRegisterValue(_ context.Context, height uint64, id flowgo.RegisterID) ([]byte, error) {
+	r.heights = append(r.heights, height)
+
+	value, ok := r.registers[id]
+	if !ok {
+		return []byte{}, nil
+	}
+	return value, nil
+}
+
+func forkBlock(height uint64) *flowgo.Block {
+	payload := flowgo.EmptyPayload()
+	return &flowgo.Block{
+		Header: &flowgo.Header{
+			ChainID: flowgo.Emulator,
+			Height:  height,
+		},
+		Payload: &payload,
+	}
+}
+
+func TestForkRegisterValues(t *testing.T) {
+	const forkHeight = 100
+
+	remoteRegister := flowgo.NewRegisterID(flowgo.HexToAddress("01"), "balance")
+	localRegister := flowgo.NewRegisterID(flowgo.HexToAddress("02"), "balance")
+	missingRegister := flowgo.NewRegisterID(flowgo.HexToAddress("03"), "balance")
+
+	tests := []struct {
+		name     string
+		register flowgo.RegisterID
+		// Height of the ledger the register is read at.
+		height uint64
+		value  []byte
+		// Heights the remote is expected to be queried at, for two consecutive reads.
+		remoteHeights []uint64
+	}{
+		{
+			name:          "remote register is fetched once at the fork height",
+			register:      remoteRegister,
+			height:        forkHeight,
+			value:         []byte("remote"),
+			remoteHeights: []uint64{forkHeight},
+		},
+		{
+			name:          "remote register is pinned to the fork height",
+			register:      remoteRegister,
+			height:        forkHeight + 10,
+			value:         []byte("remote"),
+			remoteHeights: []uint64{forkHeight},
+		},
+		{
+			name:          "local register isn't fetched",
+			register:      localRegister,
+			height:        forkHeight + 1,
+			value:         []byte("local"),
+			remoteHeights: nil,
+		},
+		{
+			name:          "missing register is cached as empty",
+			register:      missingRegister,
+			height:        forkHeight,
+			value:         []byte{},
+			remoteHeights: []uint64{forkHeight},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			remote := &memoryRemote{
+				registers: map[flowgo.RegisterID][]byte{
+					remoteRegister: []byte("remote"),
+					localRegister:  []byte("remote"),
+				},
+			}
+
+			s := New()
+			err := s.Fork(ctx, remote, forkBlock(forkHeight))
+			if err != nil {
+				t.Fatalf("failed to fork: %v", err)
+			}
+
+			err = s.SetBytesWithVersion(
+				ctx,
+				s.KeyGenerator.Storage(storage.LedgerStoreName),
+				[]byte(localRegister.String()),
+				[]byte("local"),
+				forkHeight+1,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 2; i++ {
+				ledger, err := s.LedgerByHeight(ctx, test.height)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				value, err := ledger.Get(test.register)
+				if err != nil {
+					t.Fatalf("failed to get register: %v", err)
+				}
+				if !bytes.Equal(value, test.value) {
+					t.Errorf("register value is %q, expected %q", value, test.value)
+				}
+			}
+
+			if len(remote.heights) != len(test.remoteHeights) {
+				t.Fatalf("remote was queried at heights %v, expected %v", remote.heights, test.remoteHeights)
+			}
+			for i, height := range test.remoteHeights {
+				if remote.heights[i] != height {
+					t.Errorf("remote was queried at heights %v, expected %v", remote.heights, test.remoteHeights)
+				}
+			}
+		})
+	}
+}
+
+func TestForkBlock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fork block is stored as the latest block", func(t *testing.T) {
+		s := New()
+		err := s.Fork(ctx, &memoryRemote{}, forkBlock(100))
+		if err != nil {
+			t.Fatalf("failed to fork: %v", err)
+		}
+
+		latestHeight, err := s.LatestBlockHeight(ctx)
+		if err != nil || latestHeight != 100 {
+			t.Errorf("latest block height is %d (%v), expected 100", latestHeight, err)
+		}
+
+		forkedHeight, err := s.ForkedBlockHeight(ctx)
+		if err != nil || forkedHeight != 100 {
+			t.Errorf("forked block height is %d (%v), expected 100", forkedHeight, err)
+		}
+	})
+
+	t.Run("restored fork keeps the original fork height", func(t *testing.T) {
+		fileSystem := newMemoryFileSystem()
+
+		s, err := NewPersistent(fileSystem, "state")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.Fork(ctx, &memoryRemote{}, forkBlock(100))
+		if err != nil {
+			t.Fatalf("failed to fork: %v", err)
+		}
+
+		restored, err := NewPersistent(fileSystem, "state")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		remote := &memoryRemote{}
+		err = restored.Fork(ctx, remote, forkBlock(200))
+		if err != nil {
+			t.Fatalf("failed to fork restored store: %v", err)
+		}
+
+		latestHeight, err := restored.LatestBlockHeight(ctx)
+		if err != nil || latestHeight != 100 {
+			t.Errorf("latest block height is %d (%v), expected 100", latestHeight, err)
+		}
+
+		ledger, err := restored.LedgerByHeight(ctx, 150)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ledger.Get(flowgo.NewRegisterID(flowgo.HexToAddress("01"), "balance"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(remote.heights) != 1 || remote.heights[0] != 100 {
+			t.Errorf("remote was queried at heights %v, expected [100]", remote.heights)
+		}
+	})
+
+	t.Run("existing state can't be forked", func(t *testing.T) {
+		s := New()
+		err := s.StoreBlock(ctx, forkBlock(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = s.Fork(ctx, &memoryRemote{}, forkBlock(100))
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
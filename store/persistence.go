@@ -0,0 +1,187 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/onflow/flowkit/v2"
+)
+
+const (
+	stateFormatVersion = 1
+	// Segments are merged into a single one once this limit is reached.
+	maxSegments      = 128
+	manifestFileName = "manifest.json"
+)
+
+// manifest describes which segment files make up the persisted state.
+// Segments of the current generation are applied in order to restore the state.
+type manifest struct {
+	Version    int    `json:"version"`
+	Generation uint64 `json:"generation"`
+	Segments   uint64 `json:"segments"`
+}
+
+// persistence writes changes to the file system incrementally,
+// each flush creates a new segment file with the changes since the previous flush.
+type persistence struct {
+	mu       sync.Mutex
+	fs       flowkit.ReaderWriter
+	dir      string
+	manifest manifest
+	pending  []entry
+}
+
+// loadPersistence restores the previously persisted state (if any) into the data.
+func loadPersistence(fs flowkit.ReaderWriter, dir string, d *data) (*persistence, error) {
+	p := &persistence{
+		fs:       fs,
+		dir:      dir,
+		manifest: manifest{Version: stateFormatVersion},
+	}
+
+	if _, err := fs.Stat(p.manifestPath()); err != nil {
+		// Nothing was persisted yet
+		return p, fs.MkdirAll(dir, 0755)
+	}
+
+	rawManifest, err := fs.ReadFile(p.manifestPath())
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(rawManifest, &p.manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state manifest: %w", err)
+	}
+
+	if p.manifest.Version != stateFormatVersion {
+		return nil, fmt.Errorf("unsupported state format version: %d", p.manifest.Version)
+	}
+
+	for segment := uint64(0); segment < p.manifest.Segments; segment++ {
+		entries, err := p.readSegment(p.manifest.Generation, segment)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			d.apply(e)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *persistence) record(e entry) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending = append(p.pending, e)
+}
+
+func (p *persistence) flush(d *data) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pending) == 0 {
+		return nil
+	}
+
+	if p.manifest.Segments >= maxSegments {
+		return p.compact(d)
+	}
+
+	err := p.writeSegment(p.manifest.Generation, p.manifest.Segments, p.pending)
+	if err != nil {
+		return err
+	}
+
+	p.manifest.Segments++
+	p.pending = nil
+
+	return p.writeManifest()
+}
+
+// reset replaces all persisted state with the data.
+func (p *persistence) reset(d *data) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.compact(d)
+}
+
+// compact writes all the data to a single segment of a new generation.
+// The manifest is only updated after the segment is written, so the previous state remains valid on failure.
+func (p *persistence) compact(d *data) error {
+	previous := p.manifest
+
+	err := p.writeSegment(previous.Generation+1, 0, d.entries())
+	if err != nil {
+		return err
+	}
+
+	p.manifest.Generation++
+	p.manifest.Segments = 1
+	p.pending = nil
+
+	err = p.writeManifest()
+	if err != nil {
+		return err
+	}
+
+	// File system doesn't support removing files, so we at least free up the space.
+	for segment := uint64(0); segment < previous.Segments; segment++ {
+		err := p.fs.WriteFile(p.segmentPath(previous.Generation, segment), []byte{}, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *persistence) readSegment(generation uint64, segment uint64) ([]entry, error) {
+	encoded, err := p.fs.ReadFile(p.segmentPath(generation, segment))
+	if err != nil {
+		return nil, err
+	}
+
+	// Segments are base64 encoded, since the JS file system only supports text files.
+	decoded, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid state segment %d: %w", segment, err)
+	}
+
+	return decodeEntries(decoded)
+}
+
+func (p *persistence) writeSegment(generation uint64, segment uint64, entries []entry) error {
+	encoded := base64.StdEncoding.EncodeToString(encodeEntries(entries))
+
+	return p.fs.WriteFile(p.segmentPath(generation, segment), []byte(encoded), 0644)
+}
+
+func (p *persistence) writeManifest() error {
+	rawManifest, err := json.Marshal(p.manifest)
+	if err != nil {
+		return err
+	}
+
+	return p.fs.WriteFile(p.manifestPath(), rawManifest, 0644)
+}
+
+func (p *persistence) manifestPath() string {
+	return path.Join(p.dir, manifestFileName)
+}
+
+func (p *persistence) segmentPath(generation uint64, segment uint64) string {
+	return path.Join(p.dir, fmt.Sprintf("%d-%d.segment", generation, segment))
+}
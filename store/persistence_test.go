@@ -0,0 +1,205 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// memoryFileSystem implements flowkit.ReaderWriter in memory.
+type memoryFileSystem struct {
+	files map[string][]byte
+}
+
+func newMemoryFileSystem() *memoryFileSystem {
+	return &memoryFileSystem{files: make(map[string][]byte)}
+}
+
+func (m *memoryFileSystem) ReadFile(source string) ([]byte, error) {
+	content, ok := m.files[source]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return content, nil
+}
+
+func (m *memoryFileSystem) WriteFile(filename string, data []byte, _ os.FileMode) error {
+	m.files[filename] = bytes.Clone(data)
+	return nil
+}
+
+func (m *memoryFileSystem) MkdirAll(string, os.FileMode) error {
+	return nil
+}
+
+func (m *memoryFileSystem) Stat(path string) (os.FileInfo, error) {
+	content, ok := m.files[path]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return memoryFileInfo{name: path, size: int64(len(content))}, nil
+}
+
+type memoryFileInfo struct {
+	name string
+	size int64
+}
+
+func (i memoryFileInfo) Name() string       { return i.name }
+func (i memoryFileInfo) Size() int64        { return i.size }
+func (i memoryFileInfo) Mode() fs.FileMode  { return 0644 }
+func (i memoryFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memoryFileInfo) IsDir() bool        { return false }
+func (i memoryFileInfo) Sys() any           { return nil }
+
+func TestEncodeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{
+			name:    "no entries",
+			entries: []entry{},
+		},
+		{
+			name: "unversioned entry",
+			entries: []entry{
+				{store: "blocks", key: []byte("latest"), value: []byte{1, 2, 3}},
+			},
+		},
+		{
+			name: "versioned entries with empty values",
+			entries: []entry{
+				{store: "ledger", key: []byte("a"), value: []byte{}, version: 1, versioned: true},
+				{store: "ledger", key: []byte("a"), value: []byte("value"), version: 1 << 40, versioned: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := decodeEntries(encodeEntries(test.entries))
+			if err != nil {
+				t.Fatalf("failed to decode entries: %v", err)
+			}
+
+			if !reflect.DeepEqual(decoded, test.entries) {
+				t.Errorf("decoded entries %v, expected %v", decoded, test.entries)
+			}
+		})
+	}
+}
+
+func TestDecodeTruncatedEntries(t *testing.T) {
+	encoded := encodeEntries([]entry{
+		{store: "blocks", key: []byte("latest"), value: []byte("value")},
+	})
+
+	_, err := decodeEntries(encoded[:len(encoded)-1])
+	if err == nil {
+		t.Error("expected an error for truncated entries")
+	}
+}
+
+func TestPersistenceRestore(t *testing.T) {
+	tests := []struct {
+		name    string
+		flushes int
+		// Expected manifest after all flushes.
+		generation uint64
+		segments   uint64
+	}{
+		{
+			name:       "nothing flushed",
+			flushes:    0,
+			generation: 0,
+			segments:   0,
+		},
+		{
+			name:       "single segment",
+			flushes:    1,
+			generation: 0,
+			segments:   1,
+		},
+		{
+			name:       "compacted at segment limit",
+			flushes:    maxSegments + 1,
+			generation: 1,
+			segments:   1,
+		},
+		{
+			name:       "segments written after compaction",
+			flushes:    maxSegments + 3,
+			generation: 1,
+			segments:   3,
+		},
+	}
+
+	const dir = "state"
+	ctx := context.Background()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileSystem := newMemoryFileSystem()
+
+			s, err := NewPersistent(fileSystem, dir)
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+
+			for i := 0; i < test.flushes; i++ {
+				// Overwrite the same key, so that compaction has to keep only the latest value.
+				err = s.SetBytes(ctx, "values", []byte("counter"), []byte(fmt.Sprint(i)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = s.SetBytesWithVersion(ctx, "ledger", []byte("register"), []byte(fmt.Sprint(i)), uint64(i))
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = s.flush()
+				if err != nil {
+					t.Fatalf("failed to flush: %v", err)
+				}
+			}
+
+			manifest := s.persistence.manifest
+			if manifest.Generation != test.generation || manifest.Segments != test.segments {
+				t.Errorf(
+					"manifest generation %d with %d segments, expected generation %d with %d segments",
+					manifest.Generation,
+					manifest.Segments,
+					test.generation,
+					test.segments,
+				)
+			}
+
+			restored, err := NewPersistent(fileSystem, dir)
+			if err != nil {
+				t.Fatalf("failed to restore store: %v", err)
+			}
+
+			if !reflect.DeepEqual(restored.data, s.data) {
+				t.Errorf("restored data doesn't match the persisted data")
+			}
+			if restored.persistence.manifest != manifest {
+				t.Errorf("restored manifest %v, expected %v", restored.persistence.manifest, manifest)
+			}
+		})
+	}
+}
+
+func TestPersistenceUnsupportedVersion(t *testing.T) {
+	fileSystem := newMemoryFileSystem()
+	fileSystem.files["state/"+manifestFileName] = []byte(`{"version":999}`)
+
+	_, err := NewPersistent(fileSystem, "state")
+	if err == nil {
+		t.Error("expected an error for unsupported state format version")
+	}
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/onflow/flow-emulator/storage"
+)
+
+var _ storage.SnapshotProvider = &Store{}
+
+// Snapshots are kept in memory only, so they don't survive page reloads.
+
+func (s *Store) Snapshots() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.snapshots))
+	for name := range s.snapshots {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// CreateSnapshot captures the current state under the given name.
+// Existing snapshot with the same name is overwritten.
+func (s *Store) CreateSnapshot(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots[name] = s.data.copy()
+
+	return nil
+}
+
+// LoadSnapshot replaces the current state with the snapshot.
+// The blockchain must be reloaded afterward, which is done by emulator.Blockchain.LoadSnapshot.
+func (s *Store) LoadSnapshot(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[name]
+	if !ok {
+		return fmt.Errorf("snapshot %s not found", name)
+	}
+
+	// Snapshot is copied, so that it can be loaded again later.
+	s.data = snapshot.copy()
+
+	if s.persistence != nil {
+		return s.persistence.reset(s.data)
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteSnapshot(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.snapshots[name]; !ok {
+		return fmt.Errorf("snapshot %s not found", name)
+	}
+
+	delete(s.snapshots, name)
+
+	return nil
+}
+
+func (s *Store) SupportSnapshotsWithCurrentConfig() bool {
+	return true
+}
+
+func (d *data) copy() *data {
+	c := &data{
+		values:    maps.Clone(d.values),
+		versioned: make(map[dataKey][]versionedValue, len(d.versioned)),
+	}
+
+	for key, values := range d.versioned {
+		c.versioned[key] = slices.Clone(values)
+	}
+
+	return c
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func getValue(t *testing.T, s *Store) []byte {
+	t.Helper()
+
+	value, err := s.GetBytes(context.Background(), "values", []byte("key"))
+	if err != nil {
+		t.Fatalf("failed to get value: %v", err)
+	}
+	return value
+}
+
+func setValue(t *testing.T, s *Store, value string) {
+	t.Helper()
+
+	err := s.SetBytes(context.Background(), "values", []byte("key"), []byte(value))
+	if err != nil {
+		t.Fatalf("failed to set value: %v", err)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	s := New()
+
+	setValue(t, s, "first")
+	err := s.CreateSnapshot("first")
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	setValue(t, s, "second")
+	err = s.CreateSnapshot("second")
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	names, err := s.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"first", "second"}) {
+		t.Errorf("snapshots are %v, expected [first second]", names)
+	}
+
+	// Loading the same snapshot again must restore it as it was created,
+	// even if the state was changed after the previous load.
+	for i := 0; i < 2; i++ {
+		err = s.LoadSnapshot("first")
+		if err != nil {
+			t.Fatalf("failed to load snapshot: %v", err)
+		}
+		if value := getValue(t, s); !bytes.Equal(value, []byte("first")) {
+			t.Errorf("value is %q after loading the snapshot, expected %q", value, "first")
+		}
+
+		setValue(t, s, "changed")
+	}
+
+	err = s.DeleteSnapshot("first")
+	if err != nil {
+		t.Fatalf("failed to delete snapshot: %v", err)
+	}
+
+	names, err = s.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"second"}) {
+		t.Errorf("snapshots are %v, expected [second]", names)
+	}
+}
+
+func TestSnapshotNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(s *Store) error
+	}{
+		{
+			name: "load",
+			run: func(s *Store) error {
+				return s.LoadSnapshot("missing")
+			},
+		},
+		{
+			name: "delete",
+			run: func(s *Store) error {
+				return s.DeleteSnapshot("missing")
+			},
+		},
+		{
+			name: "load deleted",
+			run: func(s *Store) error {
+				err := s.CreateSnapshot("missing")
+				if err != nil {
+					return err
+				}
+				err = s.DeleteSnapshot("missing")
+				if err != nil {
+					return err
+				}
+				return s.LoadSnapshot("missing")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.run(New())
+			if err == nil || err.Error() != "snapshot missing not found" {
+				t.Errorf("error is %v, expected snapshot missing not found", err)
+			}
+		})
+	}
+}
+
+func TestLoadSnapshotResetsPersistence(t *testing.T) {
+	const dir = "state"
+	fileSystem := newMemoryFileSystem()
+
+	s, err := NewPersistent(fileSystem, dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	setValue(t, s, "snapshot")
+	err = s.CreateSnapshot("snapshot")
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	setValue(t, s, "changed")
+	err = s.flush()
+	if err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	err = s.LoadSnapshot("snapshot")
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+
+	// Values that were flushed after the snapshot was created must not be restored.
+	restored, err := NewPersistent(fileSystem, dir)
+	if err != nil {
+		t.Fatalf("failed to restore store: %v", err)
+	}
+	if value := getValue(t, restored); !bytes.Equal(value, []byte("snapshot")) {
+		t.Errorf("restored value is %q, expected %q", value, "snapshot")
+	}
+	if !reflect.DeepEqual(restored.data, s.data) {
+		t.Errorf("restored data doesn't match the loaded snapshot")
+	}
+}
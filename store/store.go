@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-emulator/storage"
+	"github.com/onflow/flow-emulator/types"
+	"github.com/onflow/flow-go/fvm/storage/snapshot"
+	flowgo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flowkit/v2"
+)
+
+// Store implements emulator storage on top of in-memory key-value maps.
+// If created with NewPersistent, changes are also written to the project file system,
+// so that the emulator state survives page reloads.
+type Store struct {
+	storage.DefaultStore
+	mu   sync.RWMutex
+	data *data
+	// Nil if persistence is disabled.
+	persistence *persistence
+	snapshots   map[string]*data
+	// Nil if not forked from a remote network.
+	fork *fork
+}
+
+var _ storage.Store = &Store{}
+
+func New() *Store {
+	s := &Store{
+		data:      newData(),
+		snapshots: make(map[string]*data),
+	}
+	s.DataGetter = s
+	s.DataSetter = s
+	s.KeyGenerator = &storage.DefaultKeyGenerator{}
+
+	return s
+}
+
+// NewPersistent creates a store that restores and writes the emulator state to the given directory.
+func NewPersistent(fs flowkit.ReaderWriter, dir string) (*Store, error) {
+	s := New()
+
+	p, err := loadPersistence(fs, dir, s.data)
+	if err != nil {
+		return nil, err
+	}
+	s.persistence = p
+
+	return s, nil
+}
+
+func (s *Store) GetBytes(_ context.Context, store string, key []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.data.values[dataKey{store, string(key)}]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (s *Store) SetBytes(_ context.Context, store string, key []byte, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := entry{store: store, key: key, value: value}
+	s.data.apply(e)
+	s.persistence.record(e)
+
+	return nil
+}
+
+func (s *Store) GetBytesAtVersion(_ context.Context, store string, key []byte, version uint64) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := s.data.versioned[dataKey{store, string(key)}]
+
+	// Find the latest value that was set at or before the given version.
+	i := sort.Search(len(values), func(i int) bool {
+		return values[i].version > version
+	})
+	if i == 0 {
+		return nil, storage.ErrNotFound
+	}
+
+	return values[i-1].value, nil
+}
+
+func (s *Store) SetBytesWithVersion(_ context.Context, store string, key []byte, value []byte, version uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := entry{store: store, key: key, value: value, version: version, versioned: true}
+	s.data.apply(e)
+	s.persistence.record(e)
+
+	return nil
+}
+
+// CommitBlock stores all changes of the block and persists them at once.
+func (s *Store) CommitBlock(
+	ctx context.Context,
+	block flowgo.Block,
+	collections []*flowgo.LightCollection,
+	transactions map[flowgo.Identifier]*flowgo.TransactionBody,
+	transactionResults map[flowgo.Identifier]*types.StorableTransactionResult,
+	executionSnapshot *snapshot.ExecutionSnapshot,
+	events []flowgo.Event,
+) error {
+	err := s.DefaultStore.CommitBlock(
+		ctx,
+		block,
+		collections,
+		transactions,
+		transactionResults,
+		executionSnapshot,
+		events,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.flush()
+}
+
+// flush writes the changes that weren't persisted yet.
+func (s *Store) flush() error {
+	if s.persistence == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.persistence.flush(s.data)
+}
+
+type dataKey struct {
+	store string
+	key   string
+}
+
+type versionedValue struct {
+	version uint64
+	value   []byte
+}
+
+type data struct {
+	values map[dataKey][]byte
+	// Values sorted by version in ascending order.
+	versioned map[dataKey][]versionedValue
+}
+
+func newData() *data {
+	return &data{
+		values:    make(map[dataKey][]byte),
+		versioned: make(map[dataKey][]versionedValue),
+	}
+}
+
+func (d *data) apply(e entry) {
+	key := dataKey{e.store, string(e.key)}
+
+	if !e.versioned {
+		d.values[key] = e.value
+		return
+	}
+
+	values := d.versioned[key]
+	i := sort.Search(len(values), func(i int) bool {
+		return values[i].version >= e.version
+	})
+
+	if i < len(values) && values[i].version == e.version {
+		values[i].value = e.value
+		return
+	}
+
+	values = append(values, versionedValue{})
+	copy(values[i+1:], values[i:])
+	values[i] = versionedValue{version: e.version, value: e.value}
+	d.versioned[key] = values
+}
+
+// entries returns all the data as a list of entries, which can be applied to an empty data to restore it.
+func (d *data) entries() []entry {
+	entries := make([]entry, 0, len(d.values)+len(d.versioned))
+
+	for key, value := range d.values {
+		entries = append(entries, entry{
+			store: key.store,
+			key:   []byte(key.key),
+			value: value,
+		})
+	}
+
+	for key, values := range d.versioned {
+		for _, value := range values {
+			entries = append(entries, entry{
+				store:     key.store,
+				key:       []byte(key.key),
+				value:     value.value,
+				version:   value.version,
+				versioned: true,
+			})
+		}
+	}
+
+	return entries
+}
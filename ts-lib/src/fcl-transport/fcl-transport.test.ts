@@ -62,12 +62,12 @@ describe("FCL transport - blocks", async () => {
     const expected: Block = {
       blockSeals: [],
       collectionGuarantees: [],
-      signatures: [], // Not implemented
+      signatures: [], // Emulator blocks aren't signed
       height: 0,
       id: "a20c602fbee6fe4491e116403e3258e7b7924609696ab2edb9a93eed2c29e445",
       parentId:
         "0000000000000000000000000000000000000000000000000000000000000000",
-      timestamp: "2018-12-19 22:32:30.000000042 +0000 UTC",
+      timestamp: "2018-12-19T22:32:30.000000042Z",
     };
 
     expect(actual).toMatchObject(expected);
@@ -86,12 +86,12 @@ describe("FCL transport - blocks", async () => {
     const expected: Block = {
       blockSeals: [],
       collectionGuarantees: [],
-      signatures: [], // Not implemented
+      signatures: [], // Emulator blocks aren't signed
       height: 0,
       id: "a20c602fbee6fe4491e116403e3258e7b7924609696ab2edb9a93eed2c29e445",
       parentId:
         "0000000000000000000000000000000000000000000000000000000000000000",
-      timestamp: "2018-12-19 22:32:30.000000042 +0000 UTC",
+      timestamp: "2018-12-19T22:32:30.000000042Z",
     };
 
     expect(actual).toMatchObject(expected);
@@ -105,12 +105,12 @@ describe("FCL transport - blocks", async () => {
     const expected: Block = {
       blockSeals: [],
       collectionGuarantees: [],
-      signatures: [], // Not implemented
+      signatures: [], // Emulator blocks aren't signed
       height: 0,
       id: "a20c602fbee6fe4491e116403e3258e7b7924609696ab2edb9a93eed2c29e445",
       parentId:
         "0000000000000000000000000000000000000000000000000000000000000000",
-      timestamp: "2018-12-19 22:32:30.000000042 +0000 UTC",
+      timestamp: "2018-12-19T22:32:30.000000042Z",
     };
 
     expect(actual).toMatchObject(expected);
@@ -120,6 +120,8 @@ describe("FCL transport - blocks", async () => {
 // language=Cadence
 const simpleCadenceTx = `
     transaction {
+        prepare(signer: &Account) {}
+
         execute {
             log("Hello World")
         }
@@ -129,6 +131,8 @@ const simpleCadenceTx = `
 // language=Cadence
 const simpleArgsCadenceTx = `
     transaction (say: String) {
+        prepare(signer: &Account) {}
+
         execute {
             log(say)
         }
@@ -167,6 +171,17 @@ describe("FCL transport - collections", async () => {
   });
 });
 
+// language=Cadence
+const failingCadenceTx = `
+    transaction {
+        prepare(signer: &Account) {}
+
+        execute {
+            panic("Something went wrong")
+        }
+    }
+`;
+
 describe("FCL transport - transactions", async () => {
   beforeAll(prepareTests);
 
@@ -215,6 +230,25 @@ describe("FCL transport - transactions", async () => {
     expect(transactionId).toBeTypeOf("string");
   });
 
+  it("should report error code of a failed transaction", async () => {
+    const transactionId = await fcl.mutate({
+      cadence: failingCadenceTx,
+      limit: 100,
+    });
+
+    const status = await fcl
+      .send([fcl.getTransactionStatus(transactionId)])
+      .then(fcl.decode);
+
+    expect(status).toMatchObject({
+      statusCode: 1,
+      status: 4,
+      errorCode: 1101,
+      errorMessage: expect.stringContaining("Something went wrong"),
+      computationUsed: expect.any(Number),
+    });
+  });
+
   it("should get transaction by ID", async () => {
     const transactionId = await fcl.mutate({
       cadence: simpleCadenceTx,
@@ -232,13 +266,13 @@ describe("FCL transport - transactions", async () => {
       "proposer" | "address" | "keyId" | "sequenceNumber"
     > = {
       script: simpleCadenceTx,
-      authorizers: [],
+      authorizers: ["f8d6e0586b0a20c7"],
       envelopeSignatures: [],
       gasLimit: 10,
       proposalKey: {
         sequenceNumber: 0,
         keyId: 0,
-        address: "0000000000000000",
+        address: "f8d6e0586b0a20c7",
       },
       args: [],
       referenceBlockId:
@@ -249,6 +283,22 @@ describe("FCL transport - transactions", async () => {
 
     expect(actual).toMatchObject(expected);
   });
+
+  it("should get transaction arguments", async () => {
+    const transactionId = await fcl.mutate({
+      cadence: simpleArgsCadenceTx,
+      args: (arg: any, t: any) => [arg("Hello from outside", t.String)],
+      limit: 10,
+    });
+
+    const actual = await fcl
+      .send([fcl.getTransaction(transactionId)])
+      .then(fcl.decode);
+
+    expect(actual.args).toEqual([
+      { type: "String", value: "Hello from outside" },
+    ]);
+  });
 });
 
 // language=Cadence
@@ -265,6 +315,13 @@ const helloWorldWithArgsCadenceScript = `
     }
 `;
 
+// language=Cadence
+const invalidCadenceScript = `
+    access(all) fun main(): String {
+      return undefinedVariable
+    }
+`;
+
 describe("FCL transport - scripts", async () => {
   beforeAll(prepareTests);
 
@@ -284,4 +341,61 @@ describe("FCL transport - scripts", async () => {
 
     expect(result).toEqual("Hello from outside");
   });
+
+  it("should reject a script with a Cadence error", async () => {
+    await expect(
+      fcl.query({
+        cadence: invalidCadenceScript,
+      })
+    ).rejects.toMatchObject({
+      kind: "cadence",
+      position: {
+        line: 3,
+      },
+    });
+  });
+});
+
+// language=Cadence
+const createAccountCadenceTx = `
+    transaction {
+        prepare(signer: auth(BorrowValue) &Account) {
+            Account(payer: signer)
+        }
+    }
+`;
+
+describe("FCL transport - events", async () => {
+  beforeAll(prepareTests);
+
+  it("should get events for height range", async () => {
+    await fcl.mutate({
+      cadence: createAccountCadenceTx,
+      limit: 100,
+    });
+
+    const block: Block = await fcl
+      .send([fcl.getBlock(true)])
+      .then(fcl.decode);
+
+    const events = await fcl
+      .send([
+        fcl.getEventsAtBlockHeightRange(
+          "flow.AccountCreated",
+          block.height,
+          block.height
+        ),
+      ])
+      .then(fcl.decode);
+
+    expect(events).toHaveLength(1);
+    expect(events[0]).toMatchObject({
+      type: "flow.AccountCreated",
+      blockId: block.id,
+      blockHeight: block.height,
+      data: {
+        address: expect.any(String),
+      },
+    });
+  });
 });
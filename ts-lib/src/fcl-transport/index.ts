@@ -1,5 +1,8 @@
 import {
   Account,
+  Block,
+  Event,
+  InteractionAccount,
   InteractionTag,
   Transaction,
   TransactionStatus,
@@ -18,6 +21,32 @@ type NetworkParameters = {
   chainId: string;
 };
 
+// Event object as returned by serializeEvent in /js/internal_gateway.go.
+export type GatewayEvent = Omit<Event, "data"> & {
+  // JSON-Cadence encoded event value
+  payload: string;
+};
+
+// Transaction status as returned by serializeTransactionResult
+// in /js/internal_gateway.go.
+export type GatewayTransactionStatus = Omit<TransactionStatus, "events"> & {
+  events: GatewayEvent[];
+  // Flow error code (e.g. 1101), 0 if the transaction succeeded
+  errorCode: number;
+  // Always 0 unless EmulatorConfig.computationReporting is enabled
+  computationUsed: number;
+};
+
+// Calling it more than once has no effect.
+export type Unsubscribe = () => void;
+
+// Empty (or omitted) lists match all events.
+export type EventFilter = {
+  eventTypes?: string[];
+  // Addresses of contracts that emit the events
+  addresses?: string[];
+};
+
 type FclContext = {
   config: typeof fcl.config;
   ix: unknown;
@@ -28,31 +57,175 @@ type FclContext = {
 type FclOptions = unknown;
 
 export interface InternalGateway {
-  getAccount: (address: string) => JsResponse<Account>;
-  getBlockById: (id: string) => JsResponse<Account>;
-  getLatestBlock: () => JsResponse<Account>;
-  getBlockByHeight: (height: number) => JsResponse<Account>;
-  getTransaction: (id: string) => JsResponse<Transaction>;
-  getTransactionsByBlockId: (blockId: string) => JsResponse<Transaction[]>;
+  getAccount: (address: string) => Promise<JsResponse<Account>>;
+  getBlockById: (id: string) => Promise<JsResponse<Block>>;
+  getLatestBlock: () => Promise<JsResponse<Block>>;
+  getBlockByHeight: (height: number) => Promise<JsResponse<Block>>;
+  getTransaction: (id: string) => Promise<JsResponse<Transaction>>;
+  getTransactionsByBlockId: (
+    blockId: string
+  ) => Promise<JsResponse<Transaction[]>>;
   getTransactionResultsByBlockId: (
     blockId: string
-  ) => JsResponse<TransactionStatus[]>;
+  ) => Promise<JsResponse<GatewayTransactionStatus[]>>;
   getTransactionResult: (
     transactionId: string
-  ) => JsResponse<TransactionStatus>;
-  getCollection: (id: string) => JsResponse<Collection[]>;
+  ) => Promise<JsResponse<GatewayTransactionStatus>>;
+  getCollection: (id: string) => Promise<JsResponse<Collection>>;
   // JSON encoded object matching SendSignedTransactionRequest Go struct
-  sendSignedTransaction: (request: string) => JsResponse<string>;
+  sendSignedTransaction: (request: string) => Promise<JsResponse<string>>;
   // https://github.com/onflow/fcl-js/pull/1420
-  getNetworkParameters: () => JsResponse<NetworkParameters>;
-  executeScript: (request: string) => JsResponse<string>;
-  executeScriptAtHeight: (request: string) => JsResponse<string>;
-  executeScriptAtId: (request: string) => JsResponse<string>;
+  getNetworkParameters: () => Promise<JsResponse<NetworkParameters>>;
+  executeScript: (request: string) => Promise<JsResponse<string>>;
+  executeScriptAtHeight: (request: string) => Promise<JsResponse<string>>;
+  executeScriptAtId: (request: string) => Promise<JsResponse<string>>;
+  getEventsForHeightRange: (
+    eventType: string,
+    startHeight: number,
+    endHeight: number
+  ) => Promise<JsResponse<GatewayEvent[]>>;
+  getEventsForBlockIds: (
+    eventType: string,
+    blockIds: string[]
+  ) => Promise<JsResponse<GatewayEvent[]>>;
+  subscribeBlocks: (
+    callback: (block: Block) => void
+  ) => Promise<JsResponse<Unsubscribe>>;
+  // Callback is called with the current status and then on every change,
+  // until the transaction is sealed.
+  subscribeTransactionStatus: (
+    transactionId: string,
+    callback: (status: GatewayTransactionStatus) => void
+  ) => Promise<JsResponse<Unsubscribe>>;
+  // JSON encoded EventFilter
+  subscribeEvents: (
+    filter: string,
+    callback: (event: GatewayEvent) => void
+  ) => Promise<JsResponse<Unsubscribe>>;
+}
+
+/**
+ * Error format as defined by serializeError in /js/utils.go.
+ */
+export type InternalGatewayError = {
+  kind: "invalid_request" | "cadence" | "gateway";
+  message: string;
+  // Only set for Cadence errors
+  position: CadenceErrorPosition | null;
+};
+
+export type CadenceErrorPosition = {
+  location: string;
+  line: number;
+  column: number;
+};
+
+/**
+ * Result format as defined by serializeResult in /js/utils.go.
+ */
+type JsResponse<Value> =
+  | { value: Value; error: null }
+  | { value: null; error: InternalGatewayError };
+
+export class InternalGatewayResponseError extends Error {
+  public readonly kind: InternalGatewayError["kind"];
+  public readonly position: InternalGatewayError["position"];
+
+  constructor(error: InternalGatewayError) {
+    super(error.message);
+    this.name = "InternalGatewayResponseError";
+    this.kind = error.kind;
+    this.position = error.position;
+  }
+}
+
+// Go releases unsubscribe functions after the first call,
+// so calling them again would throw.
+export function unsubscribeOnce(unsubscribe: Unsubscribe): Unsubscribe {
+  let unsubscribed = false;
+  return () => {
+    if (!unsubscribed) {
+      unsubscribed = true;
+      unsubscribe();
+    }
+  };
+}
+
+export async function unwrap<Value>(
+  promise: Promise<JsResponse<Value>>
+): Promise<Value> {
+  const response = await promise;
+  if (response.error !== null) {
+    throw new InternalGatewayResponseError(response.error);
+  }
+  return response.value;
+}
+
+// Same account can authorize the transaction with multiple keys.
+function dedupe(addresses: (string | null)[]): (string | null)[] {
+  return addresses.filter((address, index) => {
+    return addresses.indexOf(address) === index;
+  });
+}
+
+// Matches SignatureRequest Go struct.
+// Accounts without a signature are skipped.
+function toSignatures(accounts: InteractionAccount[]) {
+  return accounts
+    .filter(account => Boolean(account.signature))
+    .map(account => ({
+      addr: account.addr,
+      keyId: Number(account.keyId),
+      signature: account.signature,
+    }));
+}
+
+// Same shape as produced by FCL HTTP transport, so that FCL can decode it,
+// see: https://github.com/onflow/fcl-js/blob/master/packages/transport-http/src/send-get-events.js
+function toFclEvent(event: GatewayEvent) {
+  return {
+    blockId: event.blockId,
+    blockHeight: event.blockHeight,
+    blockTimestamp: event.blockTimestamp,
+    type: event.type,
+    transactionId: event.transactionId,
+    transactionIndex: event.transactionIndex,
+    eventIndex: event.eventIndex,
+    payload: JSON.parse(event.payload),
+  };
+}
+
+function toFclTransactionStatus(status: GatewayTransactionStatus) {
+  return {
+    ...status,
+    events: status.events.map(toFclEvent),
+  };
+}
+
+export async function decodeEvent(event: GatewayEvent): Promise<Event> {
+  const [decoded] = await fcl.decode({ events: [toFclEvent(event)] });
+  return decoded;
 }
 
-// TODO: Expand to handle error cases
-type JsResponse<Value> = Value;
+// Decodes JSON-Cadence encoded value into a JS value.
+export async function decodeValue(payload: string): Promise<unknown> {
+  return fcl.decode({ encodedData: JSON.parse(payload) });
+}
+
+export async function decodeTransactionStatus(
+  status: GatewayTransactionStatus
+): Promise<TransactionStatus> {
+  return fcl.decode({ transactionStatus: toFclTransactionStatus(status) });
+}
 
+/**
+ * FCL transport that sends interactions to the in-process emulator.
+ *
+ * Subscriptions aren't wired into the transport, so FCL still polls
+ * (e.g. `fcl.tx(id).onceSealed()` and `fcl.events(type).subscribe()`).
+ * Use FlowWasm.subscribeTransactionStatus, FlowWasm.subscribeEvents
+ * and FlowWasm.subscribeBlocks to be notified as soon as blocks are committed.
+ */
 export function buildWasmTransport(internalGateway: InternalGateway) {
   return async function transportWasm(
     _ix: Interaction | Promise<Interaction>,
@@ -65,28 +238,30 @@ export function buildWasmTransport(internalGateway: InternalGateway) {
         return {
           ...context.response(),
           tag: ix.tag,
-          account: internalGateway.getAccount(ix.account.addr!),
+          account: await unwrap(internalGateway.getAccount(ix.account.addr!)),
         };
       case InteractionTag.GET_BLOCK:
         if (ix.block.isSealed) {
           return {
             ...context.response(),
             tag: ix.tag,
-            block: internalGateway.getLatestBlock(),
+            block: await unwrap(internalGateway.getLatestBlock()),
           };
         }
         if (ix.block.id) {
           return {
             ...context.response(),
             tag: ix.tag,
-            block: internalGateway.getBlockById(ix.block.id),
+            block: await unwrap(internalGateway.getBlockById(ix.block.id)),
           };
         }
         if (ix.block.height !== undefined && ix.block.height !== null) {
           return {
             ...context.response(),
             tag: ix.tag,
-            block: internalGateway.getBlockByHeight(Number(ix.block.height)),
+            block: await unwrap(
+              internalGateway.getBlockByHeight(Number(ix.block.height))
+            ),
           };
         }
         // No parameters are provided when fetching the reference block for a transaction.
@@ -94,14 +269,16 @@ export function buildWasmTransport(internalGateway: InternalGateway) {
         return {
           ...context.response(),
           tag: ix.tag,
-          block: internalGateway.getLatestBlock(),
+          block: await unwrap(internalGateway.getLatestBlock()),
         };
       case InteractionTag.GET_TRANSACTION:
         if (ix.transaction.id) {
           return {
             ...context.response(),
             tag: ix.tag,
-            transaction: internalGateway.getTransaction(ix.transaction.id),
+            transaction: await unwrap(
+              internalGateway.getTransaction(ix.transaction.id)
+            ),
           };
         }
         throw new Error("Unreachable");
@@ -110,8 +287,10 @@ export function buildWasmTransport(internalGateway: InternalGateway) {
           return {
             ...context.response(),
             tag: ix.tag,
-            transactionStatus: internalGateway.getTransactionResult(
-              ix.transaction.id
+            transactionStatus: toFclTransactionStatus(
+              await unwrap(
+                internalGateway.getTransactionResult(ix.transaction.id)
+              )
             ),
           };
         }
@@ -121,48 +300,100 @@ export function buildWasmTransport(internalGateway: InternalGateway) {
           return {
             ...context.response(),
             tag: ix.tag,
-            collection: internalGateway.getCollection(ix.collection.id),
+            collection: await unwrap(
+              internalGateway.getCollection(ix.collection.id)
+            ),
           };
         }
         throw new Error("Unreachable");
-      case InteractionTag.TRANSACTION:
+      case InteractionTag.TRANSACTION: {
+        // Payer is a list of temp IDs in newer FCL versions.
+        // See: https://github.com/onflow/fcl-js/blob/9c7873140015c9d1e28712aed93c56654f656639/packages/transport-http/src/send-transaction.js
+        const payer = ix.accounts[[ix.payer].flat()[0] as string];
+        const proposer = ix.accounts[ix.proposer as string];
+        // Payer signs the envelope, all other signers sign the payload.
+        // See: https://developers.flow.com/build/basics/transactions#signing-a-transaction
+        const accounts = Object.values(ix.accounts);
+        const payloadSignatures = toSignatures(
+          accounts.filter(account => !account.role.payer)
+        );
+        const envelopeSignatures = toSignatures(
+          accounts.filter(account => account.role.payer)
+        );
         return {
           ...context.response(),
           tag: ix.tag,
-          transaction: internalGateway.sendSignedTransaction(
-            JSON.stringify({
-              gasLimit: Number(ix.message.computeLimit ?? 10),
-              payer: ix.message.payer ?? "",
-              referenceBlockId: ix.message.refBlock ?? "",
-              script: ix.message.cadence ?? "",
-              arguments: ix.message.arguments.map(argumentId =>
-                JSON.stringify(ix.arguments[argumentId].asArgument)
-              ),
-            })
+          transaction: await unwrap(
+            internalGateway.sendSignedTransaction(
+              JSON.stringify({
+                gasLimit: Number(ix.message.computeLimit ?? 10),
+                payer: payer.addr,
+                proposalKey: {
+                  address: proposer.addr,
+                  keyId: Number(proposer.keyId),
+                  sequenceNumber: Number(proposer.sequenceNum),
+                },
+                authorizers: dedupe(
+                  ix.authorizations.map(tempId => ix.accounts[tempId].addr)
+                ),
+                payloadSignatures,
+                envelopeSignatures,
+                referenceBlockId: ix.message.refBlock ?? "",
+                script: ix.message.cadence ?? "",
+                arguments: ix.message.arguments.map(argumentId =>
+                  JSON.stringify(ix.arguments[argumentId].asArgument)
+                ),
+              })
+            )
           ),
         };
+      }
       case InteractionTag.SCRIPT:
         return {
           ...context.response(),
           tag: ix.tag,
           encodedData: JSON.parse(
-            internalGateway.executeScript(
-              JSON.stringify({
-                script: ix.message.cadence,
-                arguments: JSON.stringify(
-                  ix.message.arguments.map(
-                    argumentId => ix.arguments[argumentId].asArgument
-                  )
-                ),
-              })
+            await unwrap(
+              internalGateway.executeScript(
+                JSON.stringify({
+                  script: ix.message.cadence,
+                  arguments: JSON.stringify(
+                    ix.message.arguments.map(
+                      argumentId => ix.arguments[argumentId].asArgument
+                    )
+                  ),
+                })
+              )
             )
           ),
         };
+      case InteractionTag.GET_EVENTS: {
+        const { eventType, start, end, blockIds } = ix.events;
+        if (!eventType) {
+          throw new Error("Event type is required");
+        }
+        const events = await unwrap(
+          blockIds.length > 0
+            ? internalGateway.getEventsForBlockIds(eventType, blockIds)
+            : internalGateway.getEventsForHeightRange(
+                eventType,
+                Number(start),
+                Number(end)
+              )
+        );
+        return {
+          ...context.response(),
+          tag: ix.tag,
+          events: events.map(toFclEvent),
+        };
+      }
       case InteractionTag.GET_NETWORK_PARAMETERS:
         return {
           ...context.response(),
           tag: ix.tag,
-          networkParameters: internalGateway.getNetworkParameters(),
+          networkParameters: await unwrap(
+            internalGateway.getNetworkParameters()
+          ),
         };
       default:
         throw new Error(`Unimplemented interaction: ${JSON.stringify(ix)}`);
@@ -0,0 +1,591 @@
+import {
+  GoExecuteScriptAtHeightRequest,
+  GoExecuteScriptAtIdRequest,
+  GoExecuteScriptRequest,
+  GoFlowAccount,
+  GoFlowAccountKey,
+  GoFlowBlock,
+  GoFlowBlockEvents,
+  GoFlowCollection,
+  GoFlowEvent,
+  GoFlowGateway,
+  GoFlowTransaction,
+  GoFlowTransactionResult,
+  GoFlowTransactionSignature,
+  GoFlowTransactionStatus,
+  GoResult,
+} from "@/go-interfaces";
+
+export type NetworkId = "testnet" | "mainnet" | "previewnet";
+
+const accessNodeApis: Record<NetworkId, string> = {
+  testnet: "https://rest-testnet.onflow.org",
+  mainnet: "https://rest-mainnet.onflow.org",
+  previewnet: "https://rest-previewnet.onflow.org",
+};
+
+// Maximum block range of a single events request.
+// See: https://developers.flow.com/http-api#tag/Events/paths/~1events/get
+const maxEventsBlockRange = 250;
+
+// Gateway that uses the Flow REST API of the public access nodes.
+export class RestGateway implements GoFlowGateway {
+  constructor(private readonly network: NetworkId) {}
+
+  // https://developers.flow.com/http-api#tag/Accounts/paths/~1accounts~1{address}/get
+  async getAccount(address: string): Promise<GoResult<GoFlowAccount>> {
+    return resolveToGoResult<RestAccount, GoFlowAccount>(
+      () =>
+        this.restRequest("GET", `/v1/accounts/${address}`, {
+          query: { expand: "keys,contracts" },
+        }),
+      {
+        transform: value => {
+          const contracts: Record<string, string> = {};
+          Object.entries(value.contracts ?? {}).forEach(([name, code]) => {
+            contracts[name] = decodeBase64(code);
+          });
+          return {
+            address: value.address,
+            balance: value.balance,
+            code: "",
+            contracts: JSON.stringify(contracts),
+            keys: JSON.stringify(
+              (value.keys ?? []).map(
+                (key): GoFlowAccountKey => ({
+                  index: Number(key.index),
+                  publicKey: key.public_key,
+                  signAlgoString: key.signing_algorithm,
+                  hashAlgoString: key.hashing_algorithm,
+                  weight: Number(key.weight),
+                  sequenceNumber: Number(key.sequence_number),
+                  revoked: key.revoked,
+                })
+              )
+            ),
+          };
+        },
+      }
+    );
+  }
+
+  async executeScript(request: string): Promise<GoResult<string>> {
+    return this.executeScriptAtBlock(request, { block_height: "sealed" });
+  }
+
+  async executeScriptAtHeight(request: string): Promise<GoResult<string>> {
+    const { height }: GoExecuteScriptAtHeightRequest = JSON.parse(request);
+    return this.executeScriptAtBlock(request, { block_height: String(height) });
+  }
+
+  async executeScriptAtId(request: string): Promise<GoResult<string>> {
+    const { id }: GoExecuteScriptAtIdRequest = JSON.parse(request);
+    return this.executeScriptAtBlock(request, { block_id: id });
+  }
+
+  // Scripts are sent to the REST API directly (instead of using fcl.query),
+  // so that JSON-Cadence arguments and results are passed through unmodified.
+  // https://developers.flow.com/http-api#tag/Scripts/paths/~1scripts/post
+  private async executeScriptAtBlock(
+    request: string,
+    query: Record<string, string>
+  ): Promise<GoResult<string>> {
+    const { script, arguments: argsJson }: GoExecuteScriptRequest =
+      JSON.parse(request);
+    const args: unknown[] = argsJson ? JSON.parse(argsJson) : [];
+
+    return resolveToGoResult<string, string>(
+      () =>
+        this.restRequest("POST", "/v1/scripts", {
+          query,
+          body: {
+            script: encodeBase64(script),
+            arguments: args.map(arg => encodeBase64(JSON.stringify(arg))),
+          },
+        }),
+      {
+        transform: value => decodeBase64(value),
+      }
+    );
+  }
+
+  // https://developers.flow.com/http-api#tag/Transactions/paths/~1transactions/post
+  async sendSignedTransaction(transaction: string): Promise<GoResult<string>> {
+    const tx: GoFlowTransaction = JSON.parse(transaction);
+    return resolveToGoResult<RestTransaction, string>(
+      () =>
+        this.restRequest("POST", "/v1/transactions", {
+          body: {
+            script: encodeBase64(tx.script),
+            arguments: tx.arguments.map(encodeBase64),
+            reference_block_id: tx.referenceBlockId,
+            gas_limit: String(tx.gasLimit),
+            payer: tx.payer,
+            proposal_key: {
+              address: tx.proposalKey.address,
+              key_index: String(tx.proposalKey.keyIndex),
+              sequence_number: String(tx.proposalKey.sequenceNumber),
+            },
+            authorizers: tx.authorizers,
+            payload_signatures: tx.payloadSignatures.map(toRestSignature),
+            envelope_signatures: tx.envelopeSignatures.map(toRestSignature),
+          },
+        }),
+      {
+        transform: value => value.id,
+      }
+    );
+  }
+
+  // https://developers.flow.com/http-api#tag/Transactions/paths/~1transactions~1{id}/get
+  async getTransaction(id: string): Promise<GoResult<string>> {
+    return resolveToGoResult<RestTransaction, string>(
+      () => this.restRequest("GET", `/v1/transactions/${id}`),
+      {
+        transform: value => JSON.stringify(fromRestTransaction(value)),
+      }
+    );
+  }
+
+  // https://developers.flow.com/http-api#tag/Transactions/paths/~1transaction_results~1{transaction_id}/get
+  async getTransactionResult(id: string): Promise<GoResult<string>> {
+    return resolveToGoResult<GoFlowTransactionResult, string>(
+      async () => {
+        const result = await this.restRequest<RestTransactionResult>(
+          "GET",
+          `/v1/transaction_results/${id}`
+        );
+        return {
+          status: restTransactionStatuses[result.status],
+          errorMessage: result.error_message,
+          events: result.events.map(fromRestEvent),
+          blockId: result.block_id,
+          // Block height isn't included in the REST response
+          blockHeight: await this.getBlockHeight(result.block_id),
+          transactionId: id,
+          collectionId: result.collection_id,
+        };
+      },
+      {
+        transform: value => JSON.stringify(value),
+      }
+    );
+  }
+
+  // The REST API doesn't provide this endpoint,
+  // so transactions are fetched from each collection in the block.
+  async getTransactionsByBlockId(blockId: string): Promise<GoResult<string>> {
+    return resolveToGoResult<GoFlowTransaction[], string>(
+      async () => {
+        const block = firstBlock(
+          await this.restRequest<RestBlock[]>("GET", `/v1/blocks/${blockId}`, {
+            query: { expand: "payload" },
+          })
+        );
+        const transactions: GoFlowTransaction[] = [];
+        for (const guarantee of block.payload.collection_guarantees) {
+          const collection = await this.restRequest<RestCollection>(
+            "GET",
+            `/v1/collections/${guarantee.collection_id}`,
+            { query: { expand: "transactions" } }
+          );
+          transactions.push(
+            ...collection.transactions.map(fromRestTransaction)
+          );
+        }
+        return transactions;
+      },
+      {
+        transform: value => JSON.stringify(value),
+      }
+    );
+  }
+
+  // https://developers.flow.com/http-api#tag/Blocks/paths/~1blocks/get
+  async getLatestBlock(): Promise<GoResult<string>> {
+    return this.getBlocks("/v1/blocks", { height: "sealed" });
+  }
+
+  async getBlockByHeight(height: number): Promise<GoResult<string>> {
+    return this.getBlocks("/v1/blocks", { height: String(height) });
+  }
+
+  // https://developers.flow.com/http-api#tag/Blocks/paths/~1blocks~1{id}/get
+  async getBlockById(id: string): Promise<GoResult<string>> {
+    return this.getBlocks(`/v1/blocks/${id}`, {});
+  }
+
+  private async getBlocks(
+    path: string,
+    query: Record<string, string>
+  ): Promise<GoResult<string>> {
+    return resolveToGoResult<RestBlock[], string>(
+      () =>
+        this.restRequest("GET", path, {
+          query: { ...query, expand: "payload" },
+        }),
+      {
+        transform: blocks => {
+          const block = firstBlock(blocks);
+          return JSON.stringify({
+            id: block.header.id,
+            parentId: block.header.parent_id,
+            height: Number(block.header.height),
+            timestamp: block.header.timestamp,
+            collectionGuarantees: block.payload.collection_guarantees.map(
+              guarantee => ({ collectionId: guarantee.collection_id })
+            ),
+            blockSeals: block.payload.block_seals.map(seal => ({
+              blockId: seal.block_id,
+              executionReceiptId: seal.result_id,
+            })),
+          } satisfies GoFlowBlock);
+        },
+      }
+    );
+  }
+
+  // https://developers.flow.com/http-api#tag/Collections/paths/~1collections~1{id}/get
+  async getCollection(id: string): Promise<GoResult<string>> {
+    return resolveToGoResult<RestCollection, string>(
+      () =>
+        this.restRequest("GET", `/v1/collections/${id}`, {
+          query: { expand: "transactions" },
+        }),
+      {
+        transform: value =>
+          JSON.stringify({
+            id: value.id,
+            transactionIds: value.transactions.map(
+              transaction => transaction.id
+            ),
+          } satisfies GoFlowCollection),
+      }
+    );
+  }
+
+  // Requests are split into ranges of at most 250 blocks,
+  // which is the limit of the REST API.
+  // https://developers.flow.com/http-api#tag/Events/paths/~1events/get
+  async getEvents(
+    eventType: string,
+    startHeight: number,
+    endHeight: number
+  ): Promise<GoResult<string>> {
+    return resolveToGoResult<RestBlockEvents[], string>(
+      async () => {
+        const blockEvents: RestBlockEvents[] = [];
+        for (
+          let rangeStart = startHeight;
+          rangeStart <= endHeight;
+          rangeStart += maxEventsBlockRange
+        ) {
+          const rangeEnd = Math.min(
+            rangeStart + maxEventsBlockRange - 1,
+            endHeight
+          );
+          blockEvents.push(
+            ...(await this.restRequest<RestBlockEvents[]>("GET", "/v1/events", {
+              query: {
+                type: eventType,
+                start_height: String(rangeStart),
+                end_height: String(rangeEnd),
+              },
+            }))
+          );
+        }
+        return blockEvents;
+      },
+      {
+        transform: value =>
+          JSON.stringify(
+            value.map(
+              (blockEvents): GoFlowBlockEvents => ({
+                blockId: blockEvents.block_id,
+                blockHeight: Number(blockEvents.block_height),
+                blockTimestamp: blockEvents.block_timestamp,
+                events: blockEvents.events.map(fromRestEvent),
+              })
+            )
+          ),
+      }
+    );
+  }
+
+  // https://developers.flow.com/http-api#tag/Network-Parameters
+  async ping(): Promise<GoResult<null>> {
+    return resolveToGoResult<unknown, null>(
+      () => this.restRequest("GET", "/v1/network/parameters"),
+      {
+        transform: () => null,
+      }
+    );
+  }
+
+  isSecureConnection(): boolean {
+    return new URL(accessNodeApis[this.network]).protocol === "https:";
+  }
+
+  private async getBlockHeight(blockId: string): Promise<number> {
+    if (!blockId || /^0*$/.test(blockId)) {
+      return 0;
+    }
+    const block = firstBlock(
+      await this.restRequest<RestBlock[]>("GET", `/v1/blocks/${blockId}`)
+    );
+    return Number(block.header.height);
+  }
+
+  private async restRequest<Value>(
+    method: "GET" | "POST",
+    path: string,
+    options: {
+      query?: Record<string, string>;
+      body?: unknown;
+    } = {}
+  ): Promise<Value> {
+    const url = new URL(path, accessNodeApis[this.network]);
+    Object.entries(options.query ?? {}).forEach(([key, value]) => {
+      url.searchParams.set(key, value);
+    });
+
+    const response = await fetch(url, {
+      method,
+      headers: { "Content-Type": "application/json" },
+      body:
+        options.body === undefined ? undefined : JSON.stringify(options.body),
+    });
+
+    if (!response.ok) {
+      const errorBody = await response.json().catch(() => null);
+      throw new Error(
+        `${method} ${path} failed with status ${response.status}: ${errorBody?.message ?? response.statusText}`
+      );
+    }
+
+    return response.json();
+  }
+}
+
+/**
+ * @deprecated Renamed to RestGateway, since it uses the REST API directly.
+ */
+export class FclGateway extends RestGateway {}
+
+// Partial response types of the Flow REST API.
+// See: https://developers.flow.com/http-api
+type RestSignature = {
+  address: string;
+  key_index: string;
+  // Base64 encoded signature
+  signature: string;
+};
+
+type RestAccount = {
+  address: string;
+  balance: string;
+  // Only included with "expand=keys" query parameter
+  keys?: {
+    index: string;
+    public_key: string;
+    signing_algorithm: string;
+    hashing_algorithm: string;
+    sequence_number: string;
+    weight: string;
+    revoked: boolean;
+  }[];
+  // Only included with "expand=contracts" query parameter, values are base64 encoded
+  contracts?: Record<string, string>;
+};
+
+type RestTransaction = {
+  id: string;
+  script: string;
+  arguments: string[];
+  reference_block_id: string;
+  gas_limit: string;
+  payer: string;
+  proposal_key: {
+    address: string;
+    key_index: string;
+    sequence_number: string;
+  };
+  authorizers: string[];
+  payload_signatures: RestSignature[];
+  envelope_signatures: RestSignature[];
+};
+
+type RestEvent = {
+  type: string;
+  transaction_id: string;
+  transaction_index: string;
+  event_index: string;
+  // Base64 encoded JSON-Cadence value
+  payload: string;
+};
+
+type RestTransactionStatus =
+  | "Pending"
+  | "Finalized"
+  | "Executed"
+  | "Sealed"
+  | "Expired";
+
+const restTransactionStatuses: Record<
+  RestTransactionStatus,
+  GoFlowTransactionStatus
+> = {
+  Pending: GoFlowTransactionStatus.Pending,
+  Finalized: GoFlowTransactionStatus.Finalized,
+  Executed: GoFlowTransactionStatus.Executed,
+  Sealed: GoFlowTransactionStatus.Sealed,
+  Expired: GoFlowTransactionStatus.Expired,
+};
+
+type RestTransactionResult = {
+  block_id: string;
+  collection_id: string;
+  status: RestTransactionStatus;
+  status_code: number;
+  error_message: string;
+  events: RestEvent[];
+};
+
+type RestBlock = {
+  header: {
+    id: string;
+    parent_id: string;
+    height: string;
+    timestamp: string;
+  };
+  // Only included with "expand=payload" query parameter
+  payload: {
+    collection_guarantees: { collection_id: string }[];
+    block_seals: { block_id: string; result_id: string }[];
+  };
+};
+
+type RestCollection = {
+  id: string;
+  // Only included with "expand=transactions" query parameter
+  transactions: RestTransaction[];
+};
+
+type RestBlockEvents = {
+  block_id: string;
+  block_height: string;
+  block_timestamp: string;
+  events: RestEvent[];
+};
+
+// Block endpoints respond with a list, which is empty if the block isn't found.
+function firstBlock(blocks: RestBlock[]): RestBlock {
+  if (blocks.length === 0) {
+    throw new Error("Block not found");
+  }
+  return blocks[0];
+}
+
+function fromRestTransaction(transaction: RestTransaction): GoFlowTransaction {
+  return {
+    script: decodeBase64(transaction.script),
+    arguments: transaction.arguments.map(decodeBase64),
+    referenceBlockId: transaction.reference_block_id,
+    gasLimit: Number(transaction.gas_limit),
+    payer: transaction.payer,
+    proposalKey: {
+      address: transaction.proposal_key.address,
+      keyIndex: Number(transaction.proposal_key.key_index),
+      sequenceNumber: Number(transaction.proposal_key.sequence_number),
+    },
+    authorizers: transaction.authorizers,
+    payloadSignatures: transaction.payload_signatures.map(fromRestSignature),
+    envelopeSignatures: transaction.envelope_signatures.map(fromRestSignature),
+  };
+}
+
+function fromRestEvent(event: RestEvent): GoFlowEvent {
+  return {
+    type: event.type,
+    transactionId: event.transaction_id,
+    transactionIndex: Number(event.transaction_index),
+    eventIndex: Number(event.event_index),
+    payload: decodeBase64(event.payload),
+  };
+}
+
+function toRestSignature(
+  signature: GoFlowTransactionSignature
+): RestSignature {
+  return {
+    address: signature.address,
+    key_index: String(signature.keyIndex),
+    signature: hexToBase64(signature.signature),
+  };
+}
+
+function fromRestSignature(
+  signature: RestSignature
+): GoFlowTransactionSignature {
+  return {
+    address: signature.address,
+    keyIndex: Number(signature.key_index),
+    signature: base64ToHex(signature.signature),
+  };
+}
+
+function hexToBase64(value: string): string {
+  const hex = value.replace(/^0x/, "");
+  let binary = "";
+  for (let i = 0; i < hex.length; i += 2) {
+    binary += String.fromCharCode(parseInt(hex.slice(i, i + 2), 16));
+  }
+  return btoa(binary);
+}
+
+function base64ToHex(value: string): string {
+  const binary = atob(value);
+  let hex = "";
+  for (let i = 0; i < binary.length; i++) {
+    hex += binary.charCodeAt(i).toString(16).padStart(2, "0");
+  }
+  return hex;
+}
+
+function encodeBase64(value: string): string {
+  const bytes = new TextEncoder().encode(value);
+  let binary = "";
+  bytes.forEach(byte => {
+    binary += String.fromCharCode(byte);
+  });
+  return btoa(binary);
+}
+
+function decodeBase64(value: string): string {
+  const binary = atob(value);
+  const bytes = new Uint8Array(binary.length);
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i);
+  }
+  return new TextDecoder().decode(bytes);
+}
+
+async function resolveToGoResult<Value, TValue>(
+  asyncFunction: () => Promise<Value>,
+  options: {
+    transform: (value: Value) => TValue;
+  }
+): Promise<GoResult<TValue>> {
+  try {
+    const result = options.transform(await asyncFunction());
+    return {
+      error: null,
+      value: result,
+    };
+  } catch (error) {
+    return {
+      error: String(error),
+      value: null,
+    };
+  }
+}
@@ -38,20 +38,185 @@ export interface GoFileInfo {
 
 export type GoFlowAccount = {
   address: string;
-  balance: number;
+  // Decimal encoded UFix64 value, which may not fit into a number
+  balance: string;
   code: string;
   // JSON encoded map of contracts
   contracts: string;
-  // JSON encoded map of keys
+  // JSON encoded array of GoFlowAccountKey
   keys: string;
 };
 
 /**
- * Defines Flow gateway interface as implemented in /js/gateway.go
+ * Account key format as defined by AccountKeyJSON in /js/external_gateway.go.
+ */
+export type GoFlowAccountKey = {
+  index: number;
+  publicKey: string;
+  signAlgoString: string;
+  hashAlgoString: string;
+  weight: number;
+  sequenceNumber: number;
+  revoked: boolean;
+};
+
+/**
+ * JSON encoded script request as defined by ExecuteScriptRequest in /js/internal_gateway.go.
+ */
+export type GoExecuteScriptRequest = {
+  script: string;
+  // JSON encoded array of JSON-Cadence values
+  arguments: string;
+};
+
+export type GoExecuteScriptAtHeightRequest = GoExecuteScriptRequest & {
+  height: number;
+};
+
+export type GoExecuteScriptAtIdRequest = GoExecuteScriptRequest & {
+  id: string;
+};
+
+/**
+ * Transaction format as defined by TransactionJSON in /js/external_gateway.go.
+ */
+export type GoFlowTransaction = {
+  script: string;
+  // JSON-Cadence encoded arguments
+  arguments: string[];
+  referenceBlockId: string;
+  gasLimit: number;
+  proposalKey: {
+    address: string;
+    keyIndex: number;
+    sequenceNumber: number;
+  };
+  payer: string;
+  authorizers: string[];
+  payloadSignatures: GoFlowTransactionSignature[];
+  envelopeSignatures: GoFlowTransactionSignature[];
+};
+
+export type GoFlowTransactionSignature = {
+  address: string;
+  keyIndex: number;
+  // Hex encoded signature
+  signature: string;
+};
+
+/**
+ * Numeric values match sdk.TransactionStatus in flow-go-sdk.
+ */
+export enum GoFlowTransactionStatus {
+  Unknown = 0,
+  Pending = 1,
+  Finalized = 2,
+  Executed = 3,
+  Sealed = 4,
+  Expired = 5,
+}
+
+/**
+ * Transaction result format as defined by TransactionResultJSON in /js/external_gateway.go.
+ */
+export type GoFlowTransactionResult = {
+  status: GoFlowTransactionStatus;
+  errorMessage: string;
+  events: GoFlowEvent[];
+  blockId: string;
+  blockHeight: number;
+  transactionId: string;
+  collectionId: string;
+};
+
+export type GoFlowEvent = {
+  type: string;
+  transactionId: string;
+  transactionIndex: number;
+  eventIndex: number;
+  // JSON-Cadence encoded event value
+  payload: string;
+};
+
+/**
+ * Block format as defined by BlockJSON in /js/external_gateway.go.
+ */
+export type GoFlowBlock = {
+  id: string;
+  parentId: string;
+  height: number;
+  // RFC 3339 formatted timestamp
+  timestamp: string;
+  collectionGuarantees: { collectionId: string }[];
+  blockSeals: { blockId: string; executionReceiptId: string }[];
+};
+
+/**
+ * Collection format as defined by CollectionJSON in /js/external_gateway.go.
+ */
+export type GoFlowCollection = {
+  id: string;
+  transactionIds: string[];
+};
+
+/**
+ * Events grouped by block as defined by BlockEventsJSON in /js/external_gateway.go.
+ */
+export type GoFlowBlockEvents = {
+  blockId: string;
+  blockHeight: number;
+  // RFC 3339 formatted timestamp
+  blockTimestamp: string;
+  events: GoFlowEvent[];
+};
+
+/**
+ * Defines Flow gateway interface as implemented in /js/external_gateway.go
  */
 export interface GoFlowGateway {
   getAccount(address: string): Promise<GoResult<GoFlowAccount>>;
-  // TODO: Define other functions
+  // Accepts JSON encoded GoExecuteScriptRequest, returns JSON-Cadence encoded value.
+  executeScript(request: string): Promise<GoResult<string>>;
+  // Accepts JSON encoded GoExecuteScriptAtHeightRequest, returns JSON-Cadence encoded value.
+  executeScriptAtHeight(request: string): Promise<GoResult<string>>;
+  // Accepts JSON encoded GoExecuteScriptAtIdRequest, returns JSON-Cadence encoded value.
+  executeScriptAtId(request: string): Promise<GoResult<string>>;
+  // Accepts JSON encoded GoFlowTransaction, returns the submitted transaction ID.
+  sendSignedTransaction(transaction: string): Promise<GoResult<string>>;
+  // Returns JSON encoded GoFlowTransaction.
+  getTransaction(id: string): Promise<GoResult<string>>;
+  // Returns JSON encoded GoFlowTransactionResult.
+  getTransactionResult(id: string): Promise<GoResult<string>>;
+  // Returns JSON encoded array of GoFlowTransaction included in the block.
+  getTransactionsByBlockId(blockId: string): Promise<GoResult<string>>;
+  // Returns JSON encoded GoFlowBlock of the latest sealed block.
+  getLatestBlock(): Promise<GoResult<string>>;
+  // Returns JSON encoded GoFlowBlock.
+  getBlockByHeight(height: number): Promise<GoResult<string>>;
+  // Returns JSON encoded GoFlowBlock.
+  getBlockById(id: string): Promise<GoResult<string>>;
+  // Returns JSON encoded GoFlowCollection.
+  getCollection(id: string): Promise<GoResult<string>>;
+  // Returns JSON encoded array of GoFlowBlockEvents for each block in the (inclusive) height range.
+  getEvents(
+    eventType: string,
+    startHeight: number,
+    endHeight: number
+  ): Promise<GoResult<string>>;
+  // Returns hex encoded register value (or null if it doesn't exist).
+  // Owner and key are hex encoded. Only required for forking the network.
+  // Register values are only exposed by the gRPC Execution Data API,
+  // so RestGateway doesn't implement this method.
+  // See: https://developers.flow.com/networks/access-onchain-data/execution-data-api
+  getRegisterValue?(
+    height: number,
+    owner: string,
+    key: string
+  ): Promise<GoResult<string | null>>;
+  // Resolves without an error if the network is reachable.
+  ping(): Promise<GoResult<null>>;
+  // Whether the connection to the network is encrypted.
+  isSecureConnection(): boolean;
 }
 
 /**
@@ -1,11 +1,71 @@
-import { NetworkId } from "./gateways/fcl-gateway";
+import { NetworkId } from "./gateways/rest-gateway";
 import { GoFileSystem, GoFlowGateway, GoPrompter } from "@/go-interfaces";
-import { buildWasmTransport, InternalGateway } from "@/fcl-transport";
-import { InteractionAccount } from "@onflow/typedefs";
+import {
+  buildWasmTransport,
+  decodeEvent,
+  decodeTransactionStatus,
+  decodeValue,
+  EventFilter,
+  InternalGateway,
+  Unsubscribe,
+  unsubscribeOnce,
+  unwrap,
+} from "@/fcl-transport";
+import {
+  Block,
+  Event,
+  InteractionAccount,
+  TransactionStatus,
+} from "@onflow/typedefs";
 
-export { FclGateway } from "./gateways/fcl-gateway";
+export { RestGateway, FclGateway } from "./gateways/rest-gateway";
 export { WindowPrompter } from "./prompter/window-prompter";
 export { LightningFileSystem } from "./filesystem/lightning-file-system";
+export { InternalGatewayResponseError } from "./fcl-transport";
+export type { EventFilter, Unsubscribe } from "./fcl-transport";
+
+/**
+ * Emulator settings as defined by EmulatorConfig in /config.go.
+ */
+export type EmulatorConfig = {
+  // Verifies transaction signatures and proposal key sequence numbers.
+  // Requires the authorization functions to produce real signatures.
+  transactionValidation?: boolean;
+  storageLimit?: boolean;
+  transactionFees?: boolean;
+  // Hex encoded private key of the service account.
+  // Must match the key of the emulator account in flow.json.
+  servicePrivateKey?: string;
+  // Defaults to "ECDSA_P256"
+  serviceKeySigAlgo?: string;
+  // Defaults to "SHA3_256"
+  serviceKeyHashAlgo?: string;
+  chainId?:
+    | "flow-emulator"
+    | "flow-testnet"
+    | "flow-previewnet"
+    | "flow-mainnet";
+  scriptGasLimit?: number;
+  transactionMaxGasLimit?: number;
+  // Empty blocks are committed on startup until this height is reached.
+  initialBlockHeight?: number;
+  // Persists the emulator state to ".flow-wasm/state" in the project file system,
+  // so that it survives page reloads.
+  persistState?: boolean;
+  // Network to fork the state from, its gateway must implement
+  // GoFlowGateway.getRegisterValue. The service account comes from the
+  // forked network, so the service key only works with transaction
+  // validation disabled.
+  fork?: NetworkId;
+  // Defaults to the latest sealed block of the forked network.
+  forkHeight?: number;
+  // Reports computation used by transactions, which is otherwise always 0.
+  // Disabled by default, since the report grows with every transaction.
+  computationReporting?: boolean;
+  // Network from flow.json used to resolve contract aliases.
+  // Defaults to "emulator".
+  network?: string;
+};
 
 type FlowWasmOptions = {
   gateways: Record<NetworkId, GoFlowGateway>;
@@ -13,6 +73,186 @@ type FlowWasmOptions = {
   flowWasm: WebAssembly.WebAssemblyInstantiatedSource;
   prompter: GoPrompter;
   global: WasmGlobal;
+  emulator?: EmulatorConfig;
+};
+
+/**
+ * - auto: commits a block for every transaction (default)
+ * - manual: commits blocks only when commitBlock is called
+ * - interval: commits a block (possibly empty) at a fixed interval
+ */
+export type MiningMode = "auto" | "manual" | "interval";
+
+export type CommittedBlock = {
+  id: string;
+  height: number;
+};
+
+/**
+ * Account key as defined by AccountKeyRequest in /accounts.go.
+ */
+export type AccountKey = {
+  // Hex encoded, can be omitted if the private key is provided
+  publicKey?: string;
+  // Hex encoded, only required for saving the account to flow.json
+  privateKey?: string;
+  // Defaults to "ECDSA_P256"
+  sigAlgo?: string;
+  // Defaults to "SHA3_256"
+  hashAlgo?: string;
+  // Defaults to 1000 (full weight)
+  weight?: number;
+};
+
+/**
+ * Account creation options as defined by CreateAccountRequest in /accounts.go.
+ */
+export type CreateAccountRequest = {
+  keys: AccountKey[];
+  // Contract source code, deployed in the given order,
+  // requires the private key of the first key
+  contracts?: string[];
+  // Amount of FLOW (e.g. "10.0") transferred from the service account
+  funding?: string;
+  // Saves the account to flow.json under this name,
+  // requires the private key of the first key.
+  name?: string;
+};
+
+export type AccountSummary = {
+  address: string;
+  // Name of the matching flow.json account
+  name: string | null;
+  // UFix64 string (e.g. "0.00100000"),
+  // since JS numbers can't represent all balances exactly
+  balance: string;
+  contracts: string[];
+  keys: {
+    index: number;
+    publicKey: string;
+    sigAlgo: string;
+    hashAlgo: string;
+    weight: number;
+    revoked: boolean;
+  }[];
+};
+
+/**
+ * Key generation options as defined by GenerateKeyRequest in /keys.go.
+ */
+export type GenerateKeyRequest = {
+  // "ECDSA_P256" (default) or "ECDSA_secp256k1"
+  sigAlgo?: string;
+  // "SHA3_256" (default) or "SHA2_256"
+  hashAlgo?: string;
+  // Random seed is used if omitted
+  seed?: string;
+  // BIP-44 path of mnemonic keys, defaults to "m/44'/539'/0'/0/0"
+  derivationPath?: string;
+};
+
+export type KeyPair = {
+  // Hex encoded
+  privateKey: string;
+  // Hex encoded
+  publicKey: string;
+  sigAlgo: string;
+  hashAlgo: string;
+};
+
+export type MnemonicKeyPair = KeyPair & {
+  mnemonic: string;
+};
+
+/**
+ * Existing account as defined by SaveAccountRequest in /keys.go.
+ */
+export type SaveAccountRequest = {
+  name: string;
+  address: string;
+  // Hex encoded, saved to a separate "<name>.pkey" file
+  privateKey: string;
+  // Only 0 (default) is supported
+  keyIndex?: number;
+  // Defaults to "ECDSA_P256"
+  sigAlgo?: string;
+  // Defaults to "SHA3_256"
+  hashAlgo?: string;
+};
+
+export type SigningAccount = {
+  // With "0x" prefix
+  addr: string;
+  keyId: number;
+};
+
+export type CompositeSignature = SigningAccount & {
+  // Hex encoded
+  signature: string;
+};
+
+// https://developers.flow.com/build/basics/scripts#json-cadence
+export type JsonCadence = { type: string; value: unknown };
+
+/**
+ * Transaction result as returned by serializeProjectTransactionResult
+ * in /project.go.
+ */
+type ProjectTransactionResultResponse = {
+  id: string;
+  blockId: string;
+  blockHeight: number;
+  status: number;
+  statusString: string;
+  // Empty if the transaction succeeded
+  errorMessage: string;
+  events: {
+    type: string;
+    eventIndex: number;
+    // JSON-Cadence encoded event value
+    payload: string;
+  }[];
+};
+
+export type ProjectTransactionResult = Omit<
+  ProjectTransactionResultResponse,
+  "events"
+> & {
+  events: {
+    type: string;
+    eventIndex: number;
+    data: unknown;
+  }[];
+};
+
+/**
+ * Deployment options as defined by DeployRequest in /deployment.go.
+ */
+export type DeployOptions = {
+  // Defaults to "emulator"
+  network?: NetworkId | "emulator";
+  // Contract names, all contracts are deployed if omitted
+  contracts?: string[];
+  // flow.json account names, all accounts are deployed if omitted
+  accounts?: string[];
+  // Whether existing contracts are updated or the deployment fails,
+  // defaults to true
+  update?: boolean;
+  // Only plans the deployment without sending any transactions
+  dryRun?: boolean;
+};
+
+export type DeployedContract = {
+  name: string;
+  account: string;
+  address: string;
+  location: string;
+  // Addresses of imported contracts by contract name
+  imports: Record<string, string>;
+  // "conflict" means the contract exists, but updates are disabled
+  action: "create" | "update" | "unchanged" | "conflict";
+  // Empty for dry runs and unchanged contracts
+  transactionId: string;
 };
 
 /**
@@ -25,13 +265,58 @@ export interface WasmGlobal {
   mainnetGateway: GoFlowGateway;
   previewnetGateway: GoFlowGateway;
   prompter: GoPrompter;
+  // JSON encoded EmulatorConfig
+  emulatorConfig: string;
   // Called when the emulator starts and initializes APIs
   onStarted: () => void;
   // Provided by Go runtime
   gateway: InternalGateway;
   install: () => void;
   getLogs: () => string;
-  deploy: () => string;
+  // JSON encoded DeployOptions
+  deploy: (options?: string) => Promise<DeployedContract[]>;
+  ping: (network: string) => Promise<void>;
+  createSnapshot: (name: string) => Promise<void>;
+  loadSnapshot: (name: string) => Promise<void>;
+  listSnapshots: () => Promise<string[]>;
+  deleteSnapshot: (name: string) => Promise<void>;
+  exportState: () => Promise<Uint8Array>;
+  importState: (archive: Uint8Array) => Promise<void>;
+  setMiningMode: (mode: MiningMode, intervalMs?: number) => Promise<void>;
+  commitBlock: () => Promise<CommittedBlock>;
+  pendingTransactions: () => Promise<string[]>;
+  // Unix timestamp in milliseconds
+  setNextBlockTimestamp: (timestamp: number) => Promise<void>;
+  advanceTime: (durationMs: number) => Promise<void>;
+  mineBlocks: (count: number) => Promise<CommittedBlock>;
+  // JSON encoded CreateAccountRequest, resolves with the new address
+  createAccount: (request: string) => Promise<string>;
+  // JSON encoded AccountKey, resolves with the new key index
+  addKey: (accountName: string, key: string) => Promise<number>;
+  revokeKey: (accountName: string, keyIndex: number) => Promise<void>;
+  listAccounts: () => Promise<AccountSummary[]>;
+  // JSON encoded GenerateKeyRequest
+  generateKey: (request: string) => Promise<KeyPair>;
+  generateMnemonicKey: (request: string) => Promise<MnemonicKeyPair>;
+  deriveMnemonicKey: (request: string) => Promise<KeyPair>;
+  // JSON encoded SaveAccountRequest
+  saveAccount: (request: string) => Promise<void>;
+  // Empty account name refers to the emulator service account
+  signingAccount: (accountName: string) => Promise<SigningAccount>;
+  // Hex encoded message, including the transaction domain tag
+  signTransaction: (
+    message: string,
+    accountName: string
+  ) => Promise<CompositeSignature>;
+  // JSON encoded arguments and signers,
+  // resolves with JSON-Cadence encoded result
+  runScript: (path: string, args: string, network: string) => Promise<string>;
+  sendTransaction: (
+    path: string,
+    args: string,
+    signers: string,
+    network: string
+  ) => Promise<ProjectTransactionResultResponse>;
 }
 
 export interface GoWasmRuntime {
@@ -52,6 +337,7 @@ export class FlowWasm {
       global.mainnetGateway = this.options.gateways.mainnet;
       global.previewnetGateway = this.options.gateways.previewnet;
       global.prompter = this.options.prompter;
+      global.emulatorConfig = JSON.stringify(this.options.emulator ?? {});
       global.onStarted = resolve;
 
       goRuntime.run(this.options.flowWasm.instance);
@@ -70,24 +356,238 @@ export class FlowWasm {
     return JSON.parse(this.options.global.getLogs());
   }
 
-  public deploy() {
-    this.options.global.deploy();
+  // Deploys contracts of the flow.json deployment for the network
+  // and resolves with the contracts in deployment order.
+  public async deploy(
+    options: DeployOptions = {}
+  ): Promise<DeployedContract[]> {
+    return this.options.global.deploy(JSON.stringify(options));
+  }
+
+  // Rejects if the network is not reachable.
+  public async ping(network: NetworkId | "emulator"): Promise<void> {
+    return this.options.global.ping(network);
+  }
+
+  // Captures the current emulator state under the given name.
+  // Snapshots are kept in memory, so they don't survive page reloads.
+  public async createSnapshot(name: string): Promise<void> {
+    return this.options.global.createSnapshot(name);
+  }
+
+  public async loadSnapshot(name: string): Promise<void> {
+    return this.options.global.loadSnapshot(name);
+  }
+
+  public async listSnapshots(): Promise<string[]> {
+    return this.options.global.listSnapshots();
+  }
+
+  public async deleteSnapshot(name: string): Promise<void> {
+    return this.options.global.deleteSnapshot(name);
+  }
+
+  // Exports the full emulator state as a versioned binary archive.
+  public async exportState(): Promise<Uint8Array> {
+    return this.options.global.exportState();
+  }
+
+  // Replaces the emulator state with the archive created by exportState.
+  // Emulator must be configured with the same chain ID and service key.
+  public async importState(archive: Uint8Array): Promise<void> {
+    return this.options.global.importState(archive);
+  }
+
+  // Switching back to "auto" commits transactions that are still pending.
+  public async setMiningMode(
+    mode: MiningMode,
+    intervalMs?: number
+  ): Promise<void> {
+    return this.options.global.setMiningMode(mode, intervalMs);
+  }
+
+  // Executes pending transactions and commits them in a new block.
+  public async commitBlock(): Promise<CommittedBlock> {
+    return this.options.global.commitBlock();
+  }
+
+  // IDs of sent transactions that weren't committed yet.
+  public async pendingTransactions(): Promise<string[]> {
+    return this.options.global.pendingTransactions();
+  }
+
+  // Sets the timestamp of the next block, the following blocks continue
+  // from there. Must not be before the latest block timestamp.
+  public async setNextBlockTimestamp(timestamp: Date): Promise<void> {
+    return this.options.global.setNextBlockTimestamp(timestamp.getTime());
+  }
+
+  // Moves timestamps of the next and all following blocks forward.
+  public async advanceTime(durationMs: number): Promise<void> {
+    return this.options.global.advanceTime(durationMs);
+  }
+
+  // Commits the given number of blocks (pending transactions are included
+  // in the first one) and resolves with the last committed block.
+  public async mineBlocks(count: number): Promise<CommittedBlock> {
+    return this.options.global.mineBlocks(count);
+  }
+
+  // Creates an account paid by the service account, resolves with its address.
+  public async createAccount(request: CreateAccountRequest): Promise<string> {
+    return this.options.global.createAccount(JSON.stringify(request));
+  }
+
+  // Adds a key to the flow.json account, resolves with the new key index.
+  public async addKey(accountName: string, key: AccountKey): Promise<number> {
+    return this.options.global.addKey(accountName, JSON.stringify(key));
+  }
+
+  // Revokes a key of the flow.json account.
+  public async revokeKey(accountName: string, keyIndex: number): Promise<void> {
+    return this.options.global.revokeKey(accountName, keyIndex);
   }
 
-  // Authorization function for signing with service account
+  // Lists all accounts that exist on the emulator.
+  public async listAccounts(): Promise<AccountSummary[]> {
+    return this.options.global.listAccounts();
+  }
+
+  // Generates a new key pair, e.g. for creating a testnet account.
+  public async generateKey(request: GenerateKeyRequest = {}): Promise<KeyPair> {
+    return this.options.global.generateKey(JSON.stringify(request));
+  }
+
+  // Generates a new mnemonic and derives a key pair from it.
+  public async generateMnemonicKey(
+    request: Omit<GenerateKeyRequest, "seed"> = {}
+  ): Promise<MnemonicKeyPair> {
+    return this.options.global.generateMnemonicKey(JSON.stringify(request));
+  }
+
+  // Derives the key pair from an existing mnemonic.
+  public async deriveMnemonicKey(
+    mnemonic: string,
+    request: Omit<GenerateKeyRequest, "seed"> = {}
+  ): Promise<KeyPair> {
+    return this.options.global.deriveMnemonicKey(
+      JSON.stringify({ ...request, mnemonic })
+    );
+  }
+
+  // Saves an existing account to flow.json, with the private key
+  // stored in a separate git-ignored key file.
+  public async saveAccount(request: SaveAccountRequest): Promise<void> {
+    return this.options.global.saveAccount(JSON.stringify(request));
+  }
+
+  // Calls the callback for every committed block.
+  // Unlike FCL, which polls through the transport,
+  // subscriptions are notified as soon as blocks are committed.
+  public async subscribeBlocks(
+    callback: (block: Block) => void
+  ): Promise<Unsubscribe> {
+    return unwrap(
+      this.options.global.gateway.subscribeBlocks(callback)
+    ).then(unsubscribeOnce);
+  }
+
+  // Calls the callback with the current transaction status
+  // and then on every change, until the transaction is sealed.
+  public async subscribeTransactionStatus(
+    transactionId: string,
+    callback: (status: TransactionStatus) => void
+  ): Promise<Unsubscribe> {
+    return unwrap(
+      this.options.global.gateway.subscribeTransactionStatus(
+        transactionId,
+        status => decodeTransactionStatus(status).then(callback)
+      )
+    ).then(unsubscribeOnce);
+  }
+
+  // Calls the callback for every committed event that matches the filter.
+  public async subscribeEvents(
+    filter: EventFilter,
+    callback: (event: Event) => void
+  ): Promise<Unsubscribe> {
+    return unwrap(
+      this.options.global.gateway.subscribeEvents(
+        JSON.stringify(filter),
+        event => decodeEvent(event).then(callback)
+      )
+    ).then(unsubscribeOnce);
+  }
+
+  // Signs the hex encoded message (as provided by FCL signables)
+  // with the key of the flow.json account.
+  public async signTransaction(
+    message: string,
+    accountName: string
+  ): Promise<CompositeSignature> {
+    return this.options.global.signTransaction(message, accountName);
+  }
+
+  // Executes the script file (relative to the project root)
+  // with imports resolved for the network and decodes the result.
+  public async runScript(
+    path: string,
+    args: JsonCadence[] = [],
+    network: NetworkId | "emulator" = "emulator"
+  ): Promise<unknown> {
+    const result = await this.options.global.runScript(
+      path,
+      JSON.stringify(args),
+      network
+    );
+    return decodeValue(result);
+  }
+
+  // Sends the transaction file (relative to the project root) signed by
+  // the flow.json accounts and resolves once it's sealed. The first signer
+  // is also the proposer and payer. Signers are required on networks other
+  // than "emulator", where they default to the service account.
+  public async sendTransaction(
+    path: string,
+    args: JsonCadence[] = [],
+    signers: string[] = [],
+    network: NetworkId | "emulator" = "emulator"
+  ): Promise<ProjectTransactionResult> {
+    const result = await this.options.global.sendTransaction(
+      path,
+      JSON.stringify(args),
+      JSON.stringify(signers),
+      network
+    );
+    const events = await Promise.all(
+      result.events.map(async ({ payload, ...event }) => ({
+        ...event,
+        data: await decodeValue(payload),
+      }))
+    );
+    return { ...result, events };
+  }
+
+  // Authorization function for signing with the flow.json account.
   // https://developers.flow.com/tools/clients/fcl-js/api#authz
-  public serviceAccountAuthz() {
-    return function (authAccount: InteractionAccount): InteractionAccount {
+  public accountAuthz(accountName: string) {
+    const global = this.options.global;
+    return async function (
+      authAccount: InteractionAccount
+    ): Promise<InteractionAccount> {
+      const { addr, keyId } = await global.signingAccount(accountName);
       return {
         ...authAccount,
-        addr: "f8d6e0586b0a20c7",
-        keyId: 0,
-        signingFunction: () => ({
-          addr: "0xf8d6e0586b0a20c7",
-          keyId: 0,
-          signature: "",
-        }),
+        addr: addr.replace(/^0x/, ""),
+        keyId,
+        signingFunction: (signable: { message: string }) =>
+          global.signTransaction(signable.message, accountName),
       };
     };
   }
+
+  // Authorization function for signing with the emulator service account.
+  public serviceAccountAuthz() {
+    return this.accountAuthz("");
+  }
 }
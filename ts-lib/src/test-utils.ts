@@ -1,7 +1,7 @@
 import fs from "node:fs/promises";
 import path from "path";
 import { memfs } from "memfs";
-import { FclGateway, FlowWasm, GoWasmRuntime, WasmGlobal } from "@/index";
+import { RestGateway, FlowWasm, GoWasmRuntime, WasmGlobal } from "@/index";
 import { InMemoryFileSystem } from "@/filesystem/in-memory-file-system";
 import { NullPrompter } from "@/prompter/null-prompter";
 
@@ -62,9 +62,9 @@ export async function runTestingFlowWasm(): Promise<FlowWasm> {
     prompter: new NullPrompter(),
     global: global as unknown as WasmGlobal,
     gateways: {
-      mainnet: new FclGateway("mainnet"),
-      previewnet: new FclGateway("previewnet"),
-      testnet: new FclGateway("testnet"),
+      mainnet: new RestGateway("mainnet"),
+      previewnet: new RestGateway("previewnet"),
+      testnet: new RestGateway("testnet"),
     },
   });
 